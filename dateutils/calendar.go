@@ -0,0 +1,486 @@
+package dateutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidHolidayRule is returned when a holiday rule or weekday name cannot be parsed.
+var ErrInvalidHolidayRule = errors.New("dateutils: invalid holiday rule")
+
+// HolidayRule computes the date of a holiday in a given year.
+type HolidayRule interface {
+	// DateIn returns the month and day of the holiday in year, or false if it does not occur that year.
+	DateIn(year int) (month time.Month, day int, ok bool)
+}
+
+// Holiday is a named holiday rule.
+type Holiday struct {
+	Name string
+	Rule HolidayRule
+}
+
+type fixedHoliday struct {
+	month time.Month
+	day   int
+}
+
+func (h fixedHoliday) DateIn(year int) (time.Month, int, bool) {
+	if h.day > DaysInMonth(time.Date(year, h.month, 1, 0, 0, 0, 0, time.UTC)) {
+		return 0, 0, false
+	}
+	return h.month, h.day, true
+}
+
+type oneOffHoliday struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func (h oneOffHoliday) DateIn(year int) (time.Month, int, bool) {
+	return h.month, h.day, year == h.year
+}
+
+type nthWeekdayHoliday struct {
+	n       int
+	weekday time.Weekday
+	month   time.Month
+}
+
+func (h nthWeekdayHoliday) DateIn(year int) (time.Month, int, bool) {
+	if h.n == 0 || h.n > 5 || h.n < -5 {
+		return 0, 0, false
+	}
+	if h.n < 0 {
+		last := DaysInMonth(time.Date(year, h.month, 1, 0, 0, 0, 0, time.UTC))
+		lastWeekday := time.Date(year, h.month, last, 0, 0, 0, 0, time.UTC).Weekday()
+		day := last - daysSinceWeekStart(lastWeekday, h.weekday) + (h.n+1)*7
+		return h.month, day, day >= 1
+	}
+
+	firstWeekday := time.Date(year, h.month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+	day := 1 + daysSinceWeekStart(h.weekday, firstWeekday) + (h.n-1)*7
+	return h.month, day, day <= DaysInMonth(time.Date(year, h.month, 1, 0, 0, 0, 0, time.UTC))
+}
+
+type easterHoliday struct {
+	offset int
+}
+
+func (h easterHoliday) DateIn(year int) (time.Month, int, bool) {
+	month, day := easterSunday(year)
+	date := time.Date(year, month, day+h.offset, 0, 0, 0, 0, time.UTC)
+	return date.Month(), date.Day(), date.Year() == year
+}
+
+// FixedHoliday returns a rule for a holiday on the same date every year, such as December 25th.
+// A February 29th holiday only occurs in leap years.
+func FixedHoliday(month time.Month, day int) HolidayRule {
+	return fixedHoliday{month: month, day: day}
+}
+
+// OneOffHoliday returns a rule for a holiday that occurs on a single date.
+func OneOffHoliday(year int, month time.Month, day int) HolidayRule {
+	return oneOffHoliday{year: year, month: month, day: day}
+}
+
+// NthWeekdayHoliday returns a rule for the nth weekday of a month, such as the third Monday of January.
+// Negative values count from the end of the month: -1 is the last weekday of the month. Rules with an n of 0 or
+// outside -5 to 5 never occur, and neither do fifth weekdays in months that have only four.
+func NthWeekdayHoliday(n int, weekday time.Weekday, month time.Month) HolidayRule {
+	return nthWeekdayHoliday{n: n, weekday: weekday, month: month}
+}
+
+// EasterHoliday returns a rule for a date relative to Western (Gregorian) Easter Sunday,
+// for example -2 for Good Friday or 1 for Easter Monday.
+func EasterHoliday(offset int) HolidayRule {
+	return easterHoliday{offset: offset}
+}
+
+// easterSunday computes Gregorian Easter Sunday using the anonymous (Meeus/Jones/Butcher) algorithm.
+func easterSunday(year int) (time.Month, int) {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+
+	return time.Month((h + l - 7*m + 114) / 31), (h+l-7*m+114)%31 + 1
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+var ordinalNames = map[string]int{
+	"first": 1, "1st": 1, "second": 2, "2nd": 2, "third": 3, "3rd": 3, "fourth": 4, "4th": 4, "fifth": 5, "5th": 5,
+	"last": -1,
+}
+
+// ParseHolidayRule parses a textual holiday rule. Supported forms are:
+//
+//	"12-25": the same month and day every year.
+//	"2024-12-24": a single date.
+//	"last monday of may", "third monday of january", "1st friday of march": the nth weekday of a month.
+//	"easter", "easter+1", "easter-2": a date relative to Easter Sunday.
+//
+// Weekday and month names may be abbreviated to three letters and are case-insensitive.
+func ParseHolidayRule(rule string) (HolidayRule, error) {
+	normalized := strings.ToLower(strings.TrimSpace(rule))
+
+	if offset, ok := strings.CutPrefix(normalized, "easter"); ok {
+		offset = strings.ReplaceAll(offset, " ", "")
+		if offset == "" {
+			return EasterHoliday(0), nil
+		}
+		days, err := strconv.Atoi(offset)
+		if err != nil || (offset[0] != '+' && offset[0] != '-') {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHolidayRule, rule)
+		}
+		return EasterHoliday(days), nil
+	}
+
+	if date, err := time.Parse(time.DateOnly, normalized); err == nil {
+		return OneOffHoliday(date.Year(), date.Month(), date.Day()), nil
+	}
+
+	// parse within a leap year so that "02-29" is accepted.
+	if date, err := time.Parse("2006-01-02", "2000-"+normalized); err == nil {
+		return FixedHoliday(date.Month(), date.Day()), nil
+	}
+
+	if fields := strings.Fields(normalized); len(fields) == 4 && fields[2] == "of" {
+		n, nOk := ordinalNames[fields[0]]
+		weekday, weekdayErr := ParseWeekday(fields[1])
+		month, monthErr := time.Parse("January", capitalize(fields[3]))
+		if monthErr != nil {
+			month, monthErr = time.Parse("Jan", capitalize(fields[3]))
+		}
+		if nOk && weekdayErr == nil && monthErr == nil {
+			return NthWeekdayHoliday(n, weekday, month.Month()), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrInvalidHolidayRule, rule)
+}
+
+// ParseWeekday parses a case-insensitive weekday name such as "Monday" or "mon".
+func ParseWeekday(name string) (time.Weekday, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	for weekdayName, weekday := range weekdayNames {
+		if normalized == weekdayName || (len(normalized) == 3 && strings.HasPrefix(weekdayName, normalized)) {
+			return weekday, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown weekday %q", ErrInvalidHolidayRule, name)
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + value[1:]
+}
+
+// CalendarOptions - business calendar options.
+type CalendarOptions struct {
+	Weekend  []time.Weekday
+	Holidays []Holiday
+}
+
+func parseCalendarOptions(opts ...CalendarOptions) CalendarOptions {
+	options := CalendarOptions{
+		Weekend: []time.Weekday{time.Saturday, time.Sunday},
+	}
+
+	for _, opt := range opts {
+		if opt.Weekend != nil {
+			options.Weekend = opt.Weekend
+		}
+		if opt.Holidays != nil {
+			options.Holidays = opt.Holidays
+		}
+	}
+
+	return options
+}
+
+// CalendarConfig is the serializable form of a business calendar, as read by ParseCalendar and LoadCalendar.
+// Weekend days are weekday names, and holiday rules use the syntax of ParseHolidayRule.
+type CalendarConfig struct {
+	Weekend  []string        `json:"weekend"`
+	Holidays []HolidayConfig `json:"holidays"`
+}
+
+// HolidayConfig is the serializable form of a Holiday.
+type HolidayConfig struct {
+	Name string `json:"name"`
+	Rule string `json:"rule"`
+}
+
+// Calendar is a business calendar with configurable weekend days and holidays.
+// Dates are evaluated in the location of the times passed to its methods. It is safe for concurrent use.
+type Calendar struct {
+	weekend  [7]bool
+	workdays int
+	holidays []Holiday
+
+	mu    sync.Mutex
+	years map[int]*calendarYear
+}
+
+type calendarYear struct {
+	// businessHolidays holds the sorted day numbers of holidays that fall on non-weekend days.
+	businessHolidays []int64
+	names            map[int64]string
+}
+
+var weekendCalendar = NewCalendar()
+
+// NewCalendar creates a business calendar.
+// NewCalendar also accepts an options object with the following properties:
+//
+//	CalendarOptions.Weekend: the non-working weekdays, defaults to Saturday and Sunday.
+//	CalendarOptions.Holidays: the holidays, defaults to none.
+func NewCalendar(opts ...CalendarOptions) *Calendar {
+	options := parseCalendarOptions(opts...)
+	calendar := &Calendar{holidays: options.Holidays, years: make(map[int]*calendarYear)}
+
+	for _, weekday := range options.Weekend {
+		calendar.weekend[weekday] = true
+	}
+	for _, isWeekend := range calendar.weekend {
+		if !isWeekend {
+			calendar.workdays++
+		}
+	}
+
+	return calendar
+}
+
+// NewCalendarFromConfig creates a business calendar from its serializable form.
+// Without weekend days in the config, Saturday and Sunday are used.
+func NewCalendarFromConfig(config CalendarConfig) (*Calendar, error) {
+	var options CalendarOptions
+
+	for _, name := range config.Weekend {
+		weekday, err := ParseWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+		options.Weekend = append(options.Weekend, weekday)
+	}
+
+	for _, holiday := range config.Holidays {
+		rule, err := ParseHolidayRule(holiday.Rule)
+		if err != nil {
+			return nil, fmt.Errorf("holiday %q: %w", holiday.Name, err)
+		}
+		options.Holidays = append(options.Holidays, Holiday{Name: holiday.Name, Rule: rule})
+	}
+
+	return NewCalendar(options), nil
+}
+
+// ParseCalendar reads a business calendar in JSON form, for example:
+//
+//	{"weekend": ["saturday", "sunday"], "holidays": [{"name": "Memorial Day", "rule": "last monday of may"}]}
+func ParseCalendar(r io.Reader) (*Calendar, error) {
+	var config CalendarConfig
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("dateutils: invalid calendar: %w", err)
+	}
+
+	return NewCalendarFromConfig(config)
+}
+
+// LoadCalendar reads a business calendar in JSON form from a local file. See ParseCalendar.
+func LoadCalendar(path string) (*Calendar, error) {
+	file, err := os.Open(path) //nolint:gosec // the path is supplied by the caller on purpose
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseCalendar(file)
+}
+
+// IsWeekend returns true if the given time falls on one of the calendar's weekend days.
+func (c *Calendar) IsWeekend(t time.Time) bool {
+	return c.weekend[t.Weekday()]
+}
+
+// IsHoliday returns true if the given time falls on one of the calendar's holidays.
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	_, ok := c.HolidayName(t)
+	return ok
+}
+
+// HolidayName returns the name of the holiday on the given date, if any.
+func (c *Calendar) HolidayName(t time.Time) (string, bool) {
+	name, ok := c.year(t.Year()).names[dayNumber(t)]
+	return name, ok
+}
+
+// IsBusinessDay returns true if the given time is neither a weekend day nor a holiday.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	return !c.IsWeekend(t) && !c.IsHoliday(t)
+}
+
+// AddBusinessDays adds the specified number of business days to the given time, keeping its time of day.
+// Negative values subtract business days. The result is computed in O(weeks) rather than day by day.
+func (c *Calendar) AddBusinessDays(t time.Time, days int) time.Time {
+	if days == 0 || c.workdays == 0 {
+		return t
+	}
+
+	direction := 1
+	if days < 0 {
+		direction = -1
+		days = -days
+	}
+
+	start := dayNumber(t)
+	current := start
+	for remaining := days; remaining > 0; {
+		next := c.addWorkdays(current, remaining, direction)
+		if direction > 0 {
+			remaining = c.countHolidays(current, next)
+		} else {
+			remaining = c.countHolidays(next-1, current-1)
+		}
+		current = next
+	}
+
+	return t.AddDate(0, 0, int(current-start))
+}
+
+// NextBusinessDay returns the first business day after the given time, keeping its time of day.
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	return c.AddBusinessDays(t, 1)
+}
+
+// PreviousBusinessDay returns the last business day before the given time, keeping its time of day.
+func (c *Calendar) PreviousBusinessDay(t time.Time) time.Time {
+	return c.AddBusinessDays(t, -1)
+}
+
+// BusinessDaysBetween returns the number of business days after start up to and including end.
+// The result is negative if end is before start, so that AddBusinessDays(start, BusinessDaysBetween(start, end))
+// returns end's date whenever end is a business day.
+func (c *Calendar) BusinessDaysBetween(start, end time.Time) int {
+	from, to := dayNumber(start), dayNumber(end)
+	if to < from {
+		return -c.BusinessDaysBetween(end, start)
+	}
+
+	return c.countWorkdays(from, to) - c.countHolidays(from, to)
+}
+
+// addWorkdays moves n non-weekend days from day in the given direction, ignoring holidays.
+func (c *Calendar) addWorkdays(day int64, n, direction int) int64 {
+	weeks := (n - 1) / c.workdays
+	day += int64(weeks * 7 * direction)
+	n -= weeks * c.workdays
+
+	for n > 0 {
+		day += int64(direction)
+		if !c.weekend[weekdayOf(day)] {
+			n--
+		}
+	}
+	return day
+}
+
+// countWorkdays counts the non-weekend days in (from, to].
+func (c *Calendar) countWorkdays(from, to int64) int {
+	weeks := (to - from) / 7
+	count := int(weeks) * c.workdays
+
+	for day := from + weeks*7 + 1; day <= to; day++ {
+		if !c.weekend[weekdayOf(day)] {
+			count++
+		}
+	}
+	return count
+}
+
+// countHolidays counts the holidays falling on non-weekend days in (from, to].
+func (c *Calendar) countHolidays(from, to int64) int {
+	if len(c.holidays) == 0 || to <= from {
+		return 0
+	}
+
+	count := 0
+	for year := civilYear(from + 1); year <= civilYear(to); year++ {
+		holidays := c.year(year).businessHolidays
+		lower := sort.Search(len(holidays), func(i int) bool { return holidays[i] > from })
+		upper := sort.Search(len(holidays), func(i int) bool { return holidays[i] > to })
+		count += upper - lower
+	}
+	return count
+}
+
+func (c *Calendar) year(year int) *calendarYear {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.years[year]; ok {
+		return cached
+	}
+
+	result := &calendarYear{names: make(map[int64]string)}
+	for _, holiday := range c.holidays {
+		month, day, ok := holiday.Rule.DateIn(year)
+		if !ok {
+			continue
+		}
+
+		number := dayNumber(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+		if _, exists := result.names[number]; exists {
+			continue
+		}
+		result.names[number] = holiday.Name
+		if !c.weekend[weekdayOf(number)] {
+			result.businessHolidays = append(result.businessHolidays, number)
+		}
+	}
+	sort.Slice(result.businessHolidays, func(i, j int) bool {
+		return result.businessHolidays[i] < result.businessHolidays[j]
+	})
+
+	c.years[year] = result
+	return result
+}
+
+// dayNumber returns the number of days between 1970-01-01 and the civil date of t in its location.
+func dayNumber(t time.Time) int64 {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+func weekdayOf(day int64) time.Weekday {
+	// 1970-01-01 was a Thursday.
+	return time.Weekday(((day % 7) + 7 + int64(time.Thursday)) % 7)
+}
+
+func civilYear(day int64) int {
+	return time.Unix(day*86400, 0).UTC().Year()
+}
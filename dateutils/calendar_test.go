@@ -0,0 +1,201 @@
+package dateutils_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func newUSCalendar() *dateutils.Calendar {
+	return dateutils.NewCalendar(dateutils.CalendarOptions{
+		Holidays: []dateutils.Holiday{
+			{Name: "New Year's Day", Rule: dateutils.FixedHoliday(time.January, 1)},
+			{Name: "Martin Luther King Jr. Day", Rule: dateutils.NthWeekdayHoliday(3, time.Monday, time.January)},
+			{Name: "Good Friday", Rule: dateutils.EasterHoliday(-2)},
+			{Name: "Memorial Day", Rule: dateutils.NthWeekdayHoliday(-1, time.Monday, time.May)},
+			{Name: "Independence Day", Rule: dateutils.FixedHoliday(time.July, 4)},
+			{Name: "Labor Day", Rule: dateutils.NthWeekdayHoliday(1, time.Monday, time.September)},
+			{Name: "Thanksgiving", Rule: dateutils.NthWeekdayHoliday(4, time.Thursday, time.November)},
+			{Name: "Christmas Day", Rule: dateutils.FixedHoliday(time.December, 25)},
+			{Name: "Company Offsite", Rule: dateutils.OneOffHoliday(2024, time.December, 24)},
+		},
+	})
+}
+
+func TestHolidayRules(t *testing.T) {
+	testCases := []struct {
+		rule     string
+		year     int
+		expected string
+	}{
+		{"12-25", 2024, "2024-12-25"},
+		{"02-29", 2024, "2024-02-29"},
+		{"02-29", 2023, ""},
+		{"2024-12-24", 2024, "2024-12-24"},
+		{"2024-12-24", 2025, ""},
+		{"last monday of may", 2024, "2024-05-27"},
+		{"Last Mon of May", 2021, "2021-05-31"},
+		{"third monday of january", 2024, "2024-01-15"},
+		{"4th thursday of november", 2024, "2024-11-28"},
+		{"1st monday of september", 2024, "2024-09-02"},
+		{"fifth friday of february", 2024, ""},
+		{"fifth thursday of february", 2024, "2024-02-29"},
+		{"easter", 2000, "2000-04-23"},
+		{"easter", 2019, "2019-04-21"},
+		{"easter", 2024, "2024-03-31"},
+		{"easter", 2025, "2025-04-20"},
+		{"easter-2", 2024, "2024-03-29"},
+		{"easter + 1", 2024, "2024-04-01"},
+		{"easter+49", 2024, "2024-05-19"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.rule, func(t *testing.T) {
+			rule, err := dateutils.ParseHolidayRule(testCase.rule)
+			assert.NoError(t, err)
+
+			month, day, ok := rule.DateIn(testCase.year)
+			if testCase.expected == "" {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, testCase.expected, time.Date(testCase.year, month, day, 0, 0, 0, 0, time.UTC).Format(time.DateOnly))
+		})
+	}
+
+	for _, n := range []int{0, 6, -6, 100} {
+		for year := 2020; year <= 2030; year++ {
+			_, _, ok := dateutils.NthWeekdayHoliday(n, time.Monday, time.March).DateIn(year)
+			assert.False(t, ok, "n=%d in %d", n, year)
+		}
+	}
+	month, day, ok := dateutils.NthWeekdayHoliday(-5, time.Friday, time.March).DateIn(2024)
+	assert.True(t, ok)
+	assert.Equal(t, "2024-03-01", time.Date(2024, month, day, 0, 0, 0, 0, time.UTC).Format(time.DateOnly))
+	_, _, ok = dateutils.NthWeekdayHoliday(-5, time.Monday, time.March).DateIn(2024)
+	assert.False(t, ok)
+
+	for _, invalid := range []string{"", "13-01", "easter1", "easter+x", "last funday of may", "first monday in may"} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := dateutils.ParseHolidayRule(invalid)
+			assert.ErrorIs(t, err, dateutils.ErrInvalidHolidayRule)
+		})
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	calendar := newUSCalendar()
+
+	t.Run("IsBusinessDay", func(t *testing.T) {
+		assert.False(t, calendar.IsBusinessDay(date(2024, 5, 27)))
+		assert.False(t, calendar.IsBusinessDay(date(2024, 5, 25)))
+		assert.True(t, calendar.IsBusinessDay(date(2024, 5, 28)))
+		assert.True(t, calendar.IsWeekend(date(2024, 5, 26)))
+		assert.True(t, calendar.IsHoliday(date(2024, 3, 29)))
+
+		name, ok := calendar.HolidayName(date(2024, 11, 28))
+		assert.True(t, ok)
+		assert.Equal(t, "Thanksgiving", name)
+	})
+
+	testCases := []struct {
+		name     string
+		start    time.Time
+		days     int
+		expected time.Time
+	}{
+		{"zero", date(2024, 5, 25), 0, date(2024, 5, 25)},
+		{"over memorial day", date(2024, 5, 24), 1, date(2024, 5, 28)},
+		{"from weekend", date(2024, 5, 25), 1, date(2024, 5, 28)},
+		{"over christmas and offsite", date(2024, 12, 20), 4, date(2024, 12, 30)},
+		{"into new year", date(2024, 12, 30), 2, date(2025, 1, 2)},
+		{"backwards over good friday", date(2024, 4, 1), -1, date(2024, 3, 28)},
+		{"backwards over new year", date(2025, 1, 2), -3, date(2024, 12, 27)},
+		{"many weeks", date(2024, 1, 2), 250, date(2024, 12, 27)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual := calendar.AddBusinessDays(testCase.start, testCase.days)
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.days, calendar.BusinessDaysBetween(testCase.start, actual))
+		})
+	}
+
+	t.Run("NextBusinessDay and PreviousBusinessDay", func(t *testing.T) {
+		assert.Equal(t, date(2024, 7, 5), calendar.NextBusinessDay(date(2024, 7, 3)))
+		assert.Equal(t, date(2024, 7, 3), calendar.PreviousBusinessDay(date(2024, 7, 5)))
+	})
+
+	t.Run("matches day by day stepping", func(t *testing.T) {
+		step := func(start time.Time, days int) time.Time {
+			direction := 1
+			if days < 0 {
+				direction, days = -1, -days
+			}
+			result := start
+			for days > 0 {
+				result = result.AddDate(0, 0, direction)
+				if calendar.IsBusinessDay(result) {
+					days--
+				}
+			}
+			return result
+		}
+
+		for start := date(2023, 12, 1); start.Before(date(2025, 2, 1)); start = start.AddDate(0, 0, 3) {
+			for _, days := range []int{-40, -11, -5, -1, 1, 4, 5, 6, 23, 61} {
+				assert.Equal(t, step(start, days), calendar.AddBusinessDays(start, days), "%s %+d", start, days)
+			}
+		}
+	})
+
+	t.Run("custom weekend", func(t *testing.T) {
+		calendar := dateutils.NewCalendar(dateutils.CalendarOptions{Weekend: []time.Weekday{time.Friday, time.Saturday}})
+		assert.Equal(t, date(2024, 5, 19), calendar.AddBusinessDays(date(2024, 5, 16), 1))
+		assert.Equal(t, 5, calendar.BusinessDaysBetween(date(2024, 5, 11), date(2024, 5, 18)))
+		assert.Equal(t, -5, calendar.BusinessDaysBetween(date(2024, 5, 18), date(2024, 5, 11)))
+	})
+}
+
+func TestParseCalendar(t *testing.T) {
+	config := `{
+		"weekend": ["fri", "saturday"],
+		"holidays": [
+			{"name": "Memorial Day", "rule": "last monday of may"},
+			{"name": "Easter Monday", "rule": "easter+1"}
+		]
+	}`
+
+	calendar, err := dateutils.ParseCalendar(strings.NewReader(config))
+	assert.NoError(t, err)
+	assert.True(t, calendar.IsWeekend(date(2024, 5, 24)))
+	assert.False(t, calendar.IsWeekend(date(2024, 5, 26)))
+	assert.True(t, calendar.IsHoliday(date(2024, 5, 27)))
+	assert.True(t, calendar.IsHoliday(date(2024, 4, 1)))
+
+	path := filepath.Join(t.TempDir(), "calendar.json")
+	assert.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	loaded, err := dateutils.LoadCalendar(path)
+	assert.NoError(t, err)
+	assert.Equal(t, date(2024, 5, 26), loaded.NextBusinessDay(date(2024, 5, 23)))
+
+	_, err = dateutils.ParseCalendar(strings.NewReader(`{"holidays": [{"name": "Bad", "rule": "someday"}]}`))
+	assert.ErrorIs(t, err, dateutils.ErrInvalidHolidayRule)
+
+	_, err = dateutils.ParseCalendar(strings.NewReader(`{"weekend": ["caturday"]}`))
+	assert.ErrorIs(t, err, dateutils.ErrInvalidHolidayRule)
+
+	_, err = dateutils.LoadCalendar(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,328 @@
+package dateutils
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const timeOfDayLayout = "15:04:05.999999999"
+
+// Date is a civil date (year, month and day) without a time or a location, such as a birthday or a due date.
+// The zero value is the zero date, which is formatted as an empty string and stored as NULL.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the date with the given year, month and day, normalizing values out of range like time.Date:
+// October 32nd becomes November 1st.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseCivilDate parses a date in the ISO 8601 form "2006-01-02".
+func ParseCivilDate(value string) (Date, error) {
+	parsed, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(parsed), nil
+}
+
+// String formats the date as "2006-01-02", or returns an empty string for the zero date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero returns true for the zero date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsValid returns true if the date exists in the Gregorian calendar, so February 30th is not valid.
+func (d Date) IsValid() bool {
+	return NewDate(d.Year, d.Month, d.Day) == d
+}
+
+// In returns the first instant of the date in loc. If midnight is skipped by a DST change, this is the first
+// instant after the gap.
+func (d Date) In(loc *time.Location) time.Time {
+	return startOfDate(d.Year, d.Month, d.Day, loc)
+}
+
+// At returns the time of day on the date in loc. Times skipped by a DST gap are moved forward by the length of
+// the gap, so 02:30 on a day when clocks jump from 02:00 to 03:00 is 03:30.
+func (d Date) At(timeOfDay TimeOfDay, loc *time.Location) time.Time {
+	result := time.Date(d.Year, d.Month, d.Day,
+		timeOfDay.Hour, timeOfDay.Minute, timeOfDay.Second, timeOfDay.Nanosecond, loc)
+	if !timeOfDay.IsValid() || TimeOfDayOf(result) == timeOfDay {
+		return result
+	}
+
+	_, offsetBefore := result.Add(-12 * time.Hour).Zone()
+	utc := time.Date(d.Year, d.Month, d.Day,
+		timeOfDay.Hour, timeOfDay.Minute, timeOfDay.Second, timeOfDay.Nanosecond, time.UTC)
+	return utc.Add(-time.Duration(offsetBefore) * time.Second).In(loc)
+}
+
+// AddDays returns the date n days later, or earlier for negative n.
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, d.Month, d.Day+n)
+}
+
+// AddMonths returns the date n months later, clamping to the end of the month: January 31st plus one month is
+// February 29th (or 28th).
+func (d Date) AddMonths(n int) Date {
+	return DateOf(addMonths(d.utc(), n, MonthEndClamp))
+}
+
+// AddYears returns the date n years later, clamping February 29th to February 28th in non-leap years.
+func (d Date) AddYears(n int) Date {
+	return d.AddMonths(12 * n)
+}
+
+// DaysSince returns the number of days from other to d, negative if d is before other.
+func (d Date) DaysSince(other Date) int {
+	return int(dayNumber(d.utc()) - dayNumber(other.utc()))
+}
+
+// Compare returns -1 if d is before other, 0 if they are the same date and +1 if d is after other.
+func (d Date) Compare(other Date) int {
+	if result := cmp.Compare(d.Year, other.Year); result != 0 {
+		return result
+	}
+	if result := cmp.Compare(d.Month, other.Month); result != 0 {
+		return result
+	}
+	return cmp.Compare(d.Day, other.Day)
+}
+
+// Before returns true if d is before other.
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// After returns true if d is after other.
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
+}
+
+// Weekday returns the day of the week of the date.
+func (d Date) Weekday() time.Weekday {
+	return d.utc().Weekday()
+}
+
+// YearDay returns the day of the year of the date, in [1, 365] or [1, 366] in leap years.
+func (d Date) YearDay() int {
+	return d.utc().YearDay()
+}
+
+// DaysInMonth returns the number of days in the month of the date.
+func (d Date) DaysInMonth() int {
+	return time.Date(d.Year, d.Month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// AgeAt returns the number of whole years from d, such as a birthdate, to at.
+func (d Date) AgeAt(at Date) int {
+	age := at.Year - d.Year
+	if at.Month < d.Month || (at.Month == d.Month && at.Day < d.Day) {
+		age--
+	}
+	return age
+}
+
+// MarshalText implements encoding.TextMarshaler using the "2006-01-02" form.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. An empty string is the zero date.
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+	date, err := ParseCivilDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// Scan implements sql.Scanner for DATE columns, accepting time.Time, string and []byte values. NULL scans to the
+// zero date. The date of a time.Time is taken as is, without converting its location.
+func (d *Date) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		*d = DateOf(value)
+	case string:
+		return d.UnmarshalText([]byte(value))
+	case []byte:
+		return d.UnmarshalText(value)
+	default:
+		return fmt.Errorf("dateutils: cannot scan %T into Date", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, storing the date as "2006-01-02", or NULL for the zero date.
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (d Date) utc() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// TimeOfDay is a civil time of day without a date or a location, such as a store opening time.
+// The zero value is midnight.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// NewTimeOfDay returns the time of day with the given components, wrapping values out of range around midnight:
+// 25:00 becomes 01:00.
+func NewTimeOfDay(hour, minute, second, nanosecond int) TimeOfDay {
+	return timeOfDayAt(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(nanosecond))
+}
+
+// TimeOfDayOf returns the time of day of t in t's location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	hour, minute, second := t.Clock()
+	return TimeOfDay{Hour: hour, Minute: minute, Second: second, Nanosecond: t.Nanosecond()}
+}
+
+// ParseTimeOfDay parses a time of day in the form "15:04", "15:04:05" or "15:04:05.999999999".
+func ParseTimeOfDay(value string) (TimeOfDay, error) {
+	value = strings.TrimSpace(value)
+
+	layout := timeOfDayLayout
+	if strings.Count(value, ":") == 1 {
+		layout = "15:04"
+	}
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		return TimeOfDay{}, err
+	}
+	return TimeOfDayOf(parsed), nil
+}
+
+// String formats the time of day as "15:04:05", with as many fractional digits as needed for the nanoseconds.
+func (t TimeOfDay) String() string {
+	return time.Date(0, 1, 1, t.Hour, t.Minute, t.Second, t.Nanosecond, time.UTC).Format(timeOfDayLayout)
+}
+
+// IsValid returns true if every component is within its range, so 24:00 is not valid.
+func (t TimeOfDay) IsValid() bool {
+	return t.Hour >= 0 && t.Hour < 24 && t.Minute >= 0 && t.Minute < 60 && t.Second >= 0 && t.Second < 60 &&
+		t.Nanosecond >= 0 && t.Nanosecond < int(time.Second)
+}
+
+// SinceMidnight returns the time elapsed on a clock since midnight, ignoring DST changes.
+func (t TimeOfDay) SinceMidnight() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second + time.Duration(t.Nanosecond)
+}
+
+// Add returns the time of day d later, wrapping around midnight: 23:00 plus two hours is 01:00.
+func (t TimeOfDay) Add(d time.Duration) TimeOfDay {
+	return timeOfDayAt(t.SinceMidnight() + d)
+}
+
+// Sub returns the clock duration from other to t, negative if t is earlier in the day.
+func (t TimeOfDay) Sub(other TimeOfDay) time.Duration {
+	return t.SinceMidnight() - other.SinceMidnight()
+}
+
+// Compare returns -1 if t is earlier in the day than other, 0 if they are equal and +1 if t is later.
+func (t TimeOfDay) Compare(other TimeOfDay) int {
+	return cmp.Compare(t.SinceMidnight(), other.SinceMidnight())
+}
+
+// Before returns true if t is earlier in the day than other.
+func (t TimeOfDay) Before(other TimeOfDay) bool {
+	return t.Compare(other) < 0
+}
+
+// After returns true if t is later in the day than other.
+func (t TimeOfDay) After(other TimeOfDay) bool {
+	return t.Compare(other) > 0
+}
+
+// On returns the time of day on the given date in loc. See Date.At.
+func (t TimeOfDay) On(date Date, loc *time.Location) time.Time {
+	return date.At(t, loc)
+}
+
+// MarshalText implements encoding.TextMarshaler using the form of String.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseTimeOfDay.
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	timeOfDay, err := ParseTimeOfDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = timeOfDay
+	return nil
+}
+
+// Scan implements sql.Scanner for TIME columns, accepting time.Time, string and []byte values. NULL scans to
+// midnight.
+func (t *TimeOfDay) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*t = TimeOfDay{}
+	case time.Time:
+		*t = TimeOfDayOf(value)
+	case string:
+		return t.UnmarshalText([]byte(value))
+	case []byte:
+		return t.UnmarshalText(value)
+	default:
+		return fmt.Errorf("dateutils: cannot scan %T into TimeOfDay", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, storing the time of day in the form of String.
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+func timeOfDayAt(sinceMidnight time.Duration) TimeOfDay {
+	const day = 24 * time.Hour
+	sinceMidnight = (sinceMidnight%day + day) % day
+
+	return TimeOfDay{
+		Hour:       int(sinceMidnight / time.Hour),
+		Minute:     int(sinceMidnight % time.Hour / time.Minute),
+		Second:     int(sinceMidnight % time.Minute / time.Second),
+		Nanosecond: int(sinceMidnight % time.Second),
+	}
+}
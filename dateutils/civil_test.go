@@ -0,0 +1,156 @@
+package dateutils_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDate(t *testing.T) {
+	date := dateutils.NewDate(2024, time.January, 31)
+
+	assert.Equal(t, dateutils.Date{Year: 2024, Month: time.February, Day: 1}, dateutils.NewDate(2024, time.January, 32))
+	assert.Equal(t, "2024-01-31", date.String())
+	assert.Equal(t, "", dateutils.Date{}.String())
+	assert.True(t, date.IsValid())
+	assert.False(t, dateutils.Date{Year: 2023, Month: time.February, Day: 29}.IsValid())
+
+	assert.Equal(t, dateutils.NewDate(2024, time.February, 29), date.AddMonths(1))
+	assert.Equal(t, dateutils.NewDate(2023, time.December, 31), date.AddMonths(-1))
+	assert.Equal(t, dateutils.NewDate(2025, time.February, 28), dateutils.NewDate(2024, time.February, 29).AddYears(1))
+	assert.Equal(t, dateutils.NewDate(2024, time.March, 1), date.AddDays(30))
+	assert.Equal(t, 366, dateutils.NewDate(2025, time.January, 1).DaysSince(dateutils.NewDate(2024, time.January, 1)))
+	assert.Equal(t, -1, date.DaysSince(date.AddDays(1)))
+
+	assert.Equal(t, -1, date.Compare(date.AddDays(1)))
+	assert.Equal(t, 0, date.Compare(dateutils.NewDate(2024, time.January, 31)))
+	assert.True(t, date.Before(date.AddDays(1)))
+	assert.True(t, date.After(date.AddDays(-1)))
+
+	assert.Equal(t, time.Wednesday, date.Weekday())
+	assert.Equal(t, 31, date.YearDay())
+	assert.Equal(t, 29, dateutils.NewDate(2024, time.February, 10).DaysInMonth())
+
+	birthday := dateutils.NewDate(1990, time.June, 15)
+	assert.Equal(t, 33, birthday.AgeAt(dateutils.NewDate(2024, time.June, 14)))
+	assert.Equal(t, 34, birthday.AgeAt(dateutils.NewDate(2024, time.June, 15)))
+
+	parsed, err := dateutils.ParseCivilDate("2024-01-31")
+	assert.NoError(t, err)
+	assert.Equal(t, date, parsed)
+	_, err = dateutils.ParseCivilDate("2024-02-30")
+	assert.Error(t, err)
+}
+
+func TestDateConversion(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	// 2024-01-01 02:00 UTC is still December 31st in New York.
+	instant := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, dateutils.NewDate(2024, time.January, 1), dateutils.DateOf(instant))
+	assert.Equal(t, dateutils.NewDate(2023, time.December, 31), dateutils.DateOf(instant.In(newYork)))
+
+	date := dateutils.NewDate(2024, time.March, 10)
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, newYork), date.In(newYork))
+	assert.Equal(t, time.Date(2024, 3, 10, 9, 30, 0, 0, newYork), date.At(dateutils.NewTimeOfDay(9, 30, 0, 0), newYork))
+	assert.Equal(t, "2024-03-10T03:30:00-04:00", date.At(dateutils.NewTimeOfDay(2, 30, 0, 0), newYork).Format(time.RFC3339))
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	// midnight was skipped in Sao Paulo on 2018-11-04.
+	assert.Equal(t, "2018-11-04T01:00:00-02:00", dateutils.NewDate(2018, time.November, 4).In(saoPaulo).Format(time.RFC3339))
+}
+
+func TestTimeOfDay(t *testing.T) {
+	opening := dateutils.NewTimeOfDay(9, 30, 0, 0)
+
+	assert.Equal(t, "09:30:00", opening.String())
+	assert.Equal(t, "23:59:59.5", dateutils.NewTimeOfDay(23, 59, 59, 500000000).String())
+	assert.Equal(t, dateutils.NewTimeOfDay(1, 0, 0, 0), dateutils.NewTimeOfDay(25, 0, 0, 0))
+	assert.Equal(t, dateutils.NewTimeOfDay(23, 0, 0, 0), dateutils.NewTimeOfDay(0, -60, 0, 0))
+	assert.True(t, opening.IsValid())
+	assert.False(t, dateutils.TimeOfDay{Hour: 24}.IsValid())
+
+	assert.Equal(t, dateutils.NewTimeOfDay(1, 0, 0, 0), dateutils.NewTimeOfDay(23, 0, 0, 0).Add(2*time.Hour))
+	assert.Equal(t, 8*time.Hour, dateutils.NewTimeOfDay(17, 30, 0, 0).Sub(opening))
+	assert.Equal(t, 9*time.Hour+30*time.Minute, opening.SinceMidnight())
+	assert.True(t, opening.Before(dateutils.NewTimeOfDay(17, 0, 0, 0)))
+	assert.True(t, opening.After(dateutils.TimeOfDay{}))
+	assert.Equal(t, 0, opening.Compare(dateutils.NewTimeOfDay(9, 30, 0, 0)))
+
+	assert.Equal(t, opening, dateutils.TimeOfDayOf(time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC), opening.On(dateutils.NewDate(2024, time.May, 1), time.UTC))
+
+	for value, expected := range map[string]dateutils.TimeOfDay{
+		"09:30":           opening,
+		"09:30:00":        opening,
+		"23:59:59.123456": dateutils.NewTimeOfDay(23, 59, 59, 123456000),
+	} {
+		parsed, err := dateutils.ParseTimeOfDay(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, parsed)
+	}
+	_, err := dateutils.ParseTimeOfDay("24:00")
+	assert.Error(t, err)
+}
+
+func TestCivilJSON(t *testing.T) {
+	type store struct {
+		Opened  dateutils.Date      `json:"opened"`
+		Closed  dateutils.Date      `json:"closed"`
+		Opening dateutils.TimeOfDay `json:"opening"`
+	}
+
+	var decoded store
+	assert.NoError(t, json.Unmarshal([]byte(`{"opened": "2020-03-01", "closed": "", "opening": "09:30"}`), &decoded))
+	assert.Equal(t, store{Opened: dateutils.NewDate(2020, time.March, 1), Opening: dateutils.NewTimeOfDay(9, 30, 0, 0)}, decoded)
+
+	encoded, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"opened": "2020-03-01", "closed": "", "opening": "09:30:00"}`, string(encoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"opened": "March 1st"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"opening": "noon"}`), &decoded))
+}
+
+func TestCivilSQL(t *testing.T) {
+	var date dateutils.Date
+	var timeOfDay dateutils.TimeOfDay
+
+	for _, src := range []any{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), "2024-01-31", []byte("2024-01-31")} {
+		assert.NoError(t, date.Scan(src))
+		assert.Equal(t, dateutils.NewDate(2024, time.January, 31), date)
+	}
+	assert.NoError(t, date.Scan(nil))
+	assert.True(t, date.IsZero())
+	assert.Error(t, date.Scan(42))
+
+	for _, src := range []any{time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC), "09:30:00", []byte("09:30")} {
+		assert.NoError(t, timeOfDay.Scan(src))
+		assert.Equal(t, dateutils.NewTimeOfDay(9, 30, 0, 0), timeOfDay)
+	}
+	assert.NoError(t, timeOfDay.Scan(nil))
+	assert.Equal(t, dateutils.TimeOfDay{}, timeOfDay)
+	assert.Error(t, timeOfDay.Scan(1.5))
+
+	value, err := dateutils.NewDate(2024, time.January, 31).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, driver.Value("2024-01-31"), value)
+
+	value, err = dateutils.Date{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = dateutils.NewTimeOfDay(9, 30, 0, 0).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, driver.Value("09:30:00"), value)
+}
@@ -0,0 +1,303 @@
+package dateutils
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock is a source of the current time and of timers, so that time-dependent code can be tested with a FakeClock.
+// RealClock uses the time package. Method values such as clock.Now can be passed wherever a func() time.Time is
+// expected, such as urlutils.SignOptions.Now.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Until(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a single event created by a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered. It is nil for timers created by AfterFunc.
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// RealClock is the Clock of the time package.
+type RealClock struct{}
+
+// Now returns time.Now().
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns time.Since(t).
+func (RealClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// Until returns time.Until(t).
+func (RealClock) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+// Sleep calls time.Sleep(d).
+func (RealClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// After returns time.After(d).
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTimer returns a Timer wrapping time.NewTimer(d).
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// NewTicker returns a Ticker wrapping time.NewTicker(d).
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+// AfterFunc returns a Timer wrapping time.AfterFunc(d, f).
+func (RealClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// FakeClock is a Clock whose time only changes when Set or Advance is called. Timers, tickers, After and Sleep fire
+// when the clock is moved past their deadline, in deadline order, with Now reporting each deadline as it fires.
+// Like the time package, channels have a buffer of one and ticks are dropped for slow receivers.
+// A FakeClock is safe for concurrent use.
+type FakeClock struct {
+	mutex   sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	clock := &FakeClock{now: now}
+	clock.changed = sync.NewCond(&clock.mutex)
+	return clock
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// Since returns the fake time elapsed since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Until returns the fake time remaining until t.
+func (c *FakeClock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+// Sleep blocks until the clock has been advanced by d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// After returns a channel that receives the fake time once the clock has been advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer returns a Timer that fires once the clock has been advanced by d.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return c.addWaiter(&fakeTimer{clock: c, channel: make(chan time.Time, 1)}, d)
+}
+
+// NewTicker returns a Ticker that fires every d of fake time. It panics if d is not positive.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("dateutils: non-positive interval for NewTicker")
+	}
+	return fakeTicker{c.addWaiter(&fakeTimer{clock: c, channel: make(chan time.Time, 1), period: d}, d)}
+}
+
+// AfterFunc returns a Timer that calls f in its own goroutine once the clock has been advanced by d.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	return c.addWaiter(&fakeTimer{clock: c, callback: f}, d)
+}
+
+// Set moves the clock to t, firing every timer and ticker whose deadline is not after t. Moving the clock backwards
+// fires nothing.
+func (c *FakeClock) Set(t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for {
+		next := c.nextDue(t)
+		if next == nil {
+			break
+		}
+		c.now = next.deadline
+		next.fire()
+	}
+	c.now = t
+	c.changed.Broadcast()
+}
+
+// Advance moves the clock forward by d. See Set.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Waiters returns the number of active timers, tickers and sleepers.
+func (c *FakeClock) Waiters() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least n timers, tickers or sleepers are active, so that a test can advance the clock
+// only once the code under test is waiting on it.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+func (c *FakeClock) addWaiter(timer *fakeTimer, d time.Duration) *fakeTimer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.schedule(timer, d)
+	return timer
+}
+
+// schedule activates timer with a deadline d from now, firing it at once if d is not positive.
+// The mutex must be held.
+func (c *FakeClock) schedule(timer *fakeTimer, d time.Duration) {
+	timer.deadline = c.now.Add(d)
+	if d <= 0 && timer.period == 0 {
+		timer.fire()
+		return
+	}
+	c.waiters = append(c.waiters, timer)
+	c.changed.Broadcast()
+}
+
+// nextDue returns the active timer with the earliest deadline not after t. The mutex must be held.
+func (c *FakeClock) nextDue(t time.Time) *fakeTimer {
+	var next *fakeTimer
+	for _, timer := range c.waiters {
+		if !timer.deadline.After(t) && (next == nil || timer.deadline.Before(next.deadline)) {
+			next = timer
+		}
+	}
+	return next
+}
+
+// remove deactivates timer, returning whether it was active. The mutex must be held.
+func (c *FakeClock) remove(timer *fakeTimer) bool {
+	index := slices.Index(c.waiters, timer)
+	if index < 0 {
+		return false
+	}
+	c.waiters = slices.Delete(c.waiters, index, index+1)
+	return true
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	channel  chan time.Time
+	callback func()
+	period   time.Duration
+	deadline time.Time
+}
+
+// fire delivers the timer and reschedules tickers. The clock's mutex must be held.
+func (t *fakeTimer) fire() {
+	t.clock.remove(t)
+
+	if t.callback != nil {
+		go t.callback()
+	} else {
+		select {
+		case t.channel <- t.clock.now:
+		default:
+		}
+	}
+
+	if t.period > 0 {
+		t.deadline = t.deadline.Add(t.period)
+		t.clock.waiters = append(t.clock.waiters, t)
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.channel
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	active := t.clock.remove(t)
+	if t.period > 0 {
+		t.period = d
+	}
+	t.clock.schedule(t, d)
+	return active
+}
+
+// fakeTicker adapts fakeTimer to the Ticker interface, whose Stop and Reset return nothing.
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("dateutils: non-positive interval for Ticker.Reset")
+	}
+	t.fakeTimer.Reset(d)
+}
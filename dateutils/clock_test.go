@@ -0,0 +1,180 @@
+package dateutils_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var clockStart = time.Date(2024, time.January, 31, 23, 59, 0, 0, time.UTC)
+
+func received(channel <-chan time.Time) (time.Time, bool) {
+	select {
+	case value := <-channel:
+		return value, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	var clock dateutils.Clock = dateutils.RealClock{}
+
+	before := time.Now()
+	assert.False(t, clock.Now().Before(before))
+	assert.GreaterOrEqual(t, clock.Since(before), time.Duration(0))
+	assert.Greater(t, clock.Until(before.Add(time.Hour)), time.Duration(0))
+
+	clock.Sleep(time.Millisecond)
+	<-clock.After(time.Millisecond)
+
+	timer := clock.NewTimer(time.Millisecond)
+	<-timer.C()
+	assert.False(t, timer.Stop())
+
+	ticker := clock.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Reset(2 * time.Millisecond)
+	ticker.Stop()
+
+	done := make(chan struct{})
+	clock.AfterFunc(time.Millisecond, func() { close(done) })
+	<-done
+}
+
+func TestFakeClock(t *testing.T) {
+	t.Run("now, set and advance", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(clockStart)
+		assert.Equal(t, clockStart, clock.Now())
+
+		clock.Advance(time.Minute)
+		assert.Equal(t, clockStart.Add(time.Minute), clock.Now())
+		assert.Equal(t, time.Minute, clock.Since(clockStart))
+		assert.Equal(t, -time.Minute, clock.Until(clockStart))
+
+		clock.Set(clockStart)
+		assert.Equal(t, clockStart, clock.Now())
+	})
+
+	t.Run("timer fires on advance", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(clockStart)
+		timer := clock.NewTimer(time.Second)
+
+		clock.Advance(999 * time.Millisecond)
+		_, fired := received(timer.C())
+		assert.False(t, fired)
+
+		clock.Advance(time.Millisecond)
+		value, fired := received(timer.C())
+		assert.True(t, fired)
+		assert.Equal(t, clockStart.Add(time.Second), value)
+		assert.False(t, timer.Stop())
+	})
+
+	t.Run("timer stop and reset", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(clockStart)
+		timer := clock.NewTimer(time.Second)
+
+		assert.True(t, timer.Stop())
+		clock.Advance(time.Second)
+		_, fired := received(timer.C())
+		assert.False(t, fired)
+
+		assert.False(t, timer.Reset(time.Second))
+		assert.True(t, timer.Reset(2*time.Second))
+		clock.Advance(time.Second)
+		_, fired = received(timer.C())
+		assert.False(t, fired)
+		clock.Advance(time.Second)
+		_, fired = received(timer.C())
+		assert.True(t, fired)
+	})
+
+	t.Run("ticker fires in deadline order and drops ticks", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(clockStart)
+		ticker := clock.NewTicker(time.Second)
+		var fired []time.Time
+		clock.AfterFunc(1500*time.Millisecond, func() {})
+
+		clock.Advance(time.Second)
+		value, ok := received(ticker.C())
+		assert.True(t, ok)
+		fired = append(fired, value)
+
+		clock.Advance(3 * time.Second)
+		value, ok = received(ticker.C())
+		assert.True(t, ok)
+		fired = append(fired, value)
+		_, ok = received(ticker.C())
+		assert.False(t, ok)
+
+		assert.Equal(t, []time.Time{clockStart.Add(time.Second), clockStart.Add(2 * time.Second)}, fired)
+		assert.Equal(t, 1, clock.Waiters())
+
+		ticker.Reset(10 * time.Second)
+		clock.Advance(5 * time.Second)
+		_, ok = received(ticker.C())
+		assert.False(t, ok)
+
+		ticker.Stop()
+		assert.Equal(t, 0, clock.Waiters())
+		assert.Panics(t, func() { clock.NewTicker(0) })
+	})
+
+	t.Run("after func runs on advance", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(clockStart)
+		var wait sync.WaitGroup
+		wait.Add(1)
+		var firedAt time.Time
+		clock.AfterFunc(time.Minute, func() {
+			firedAt = clock.Now()
+			wait.Done()
+		})
+
+		clock.Advance(time.Hour)
+		wait.Wait()
+		assert.Equal(t, clockStart.Add(time.Hour), firedAt)
+	})
+
+	t.Run("sleep and block until", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(clockStart)
+		done := make(chan time.Time)
+		go func() {
+			clock.Sleep(time.Minute)
+			done <- clock.Now()
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		assert.Equal(t, clockStart.Add(time.Minute), <-done)
+
+		<-clock.After(0)
+	})
+
+	t.Run("moving backwards fires nothing", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(clockStart)
+		timer := clock.NewTimer(time.Second)
+		clock.Set(clockStart.Add(-time.Hour))
+		_, fired := received(timer.C())
+		assert.False(t, fired)
+		assert.Equal(t, 1, clock.Waiters())
+	})
+}
+
+func TestClockVariants(t *testing.T) {
+	clock := dateutils.NewFakeClock(clockStart)
+
+	assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), dateutils.GetFirstDayOfMonthWithClock(clock))
+	assert.Equal(t, time.Date(2024, time.January, 31, 23, 59, 59, 0, time.UTC), dateutils.GetLastDayOfMonthWithClock(clock))
+
+	birthdate := time.Date(2000, time.February, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 23, dateutils.AgeWithClock(birthdate, clock))
+
+	// crossing the month boundary is deterministic.
+	clock.Advance(time.Minute)
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), dateutils.GetFirstDayOfMonthWithClock(clock))
+	assert.Equal(t, 24, dateutils.AgeWithClock(birthdate, clock))
+}
@@ -0,0 +1,782 @@
+package dateutils
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCron is returned when a cron expression cannot be parsed.
+var ErrInvalidCron = errors.New("dateutils: invalid cron expression")
+
+// cronSearchYears bounds the search for a matching time, so that schedules such as "0 0 30 2 *" terminate.
+const cronSearchYears = 100
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// CronOptions - cron schedule options.
+type CronOptions struct {
+	Location *time.Location
+}
+
+func parseCronOptions(opts ...CronOptions) CronOptions {
+	options := CronOptions{}
+
+	for _, opt := range opts {
+		if opt.Location != nil {
+			options.Location = opt.Location
+		}
+	}
+
+	return options
+}
+
+// CronSchedule is a parsed cron expression.
+type CronSchedule struct {
+	expression string
+	location   *time.Location
+	hasSeconds bool
+
+	fields [6]string
+
+	seconds, minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	anyDayOfMonth, anyDayOfWeek                              bool
+
+	// lastDayOffsets holds n for "L" (0) and "L-n" entries in the day of month field.
+	lastDayOffsets []int
+	// nearestWeekdays holds n for "nW" entries; lastWeekday is set for "LW".
+	nearestWeekdays []int
+	lastWeekday     bool
+	// lastWeekdaysOf holds the weekdays of "nL" entries; nthWeekdays the weekday and ordinal of "n#k" entries.
+	lastWeekdaysOf uint64
+	nthWeekdays    [][2]int
+}
+
+const (
+	cronSecond = iota
+	cronMinute
+	cronHour
+	cronDayOfMonth
+	cronMonth
+	cronDayOfWeek
+)
+
+// ParseCron parses a cron expression with 5 fields (minute hour day-of-month month day-of-week) or 6 fields (with a
+// leading seconds field), or one of the macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly.
+//
+// Fields accept numbers, names (JAN-DEC, SUN-SAT), lists, ranges and steps ("1,15", "MON-FRI", "*/15", "9-17/2").
+// The day of month field also accepts "L" (last day), "L-n" (n days before the last day), "nW" (the weekday nearest
+// day n) and "LW" (the last weekday); the day of week field accepts "nL" (the last given weekday of the month) and
+// "n#k" (the kth given weekday of the month). "?" is a synonym for "*" in both day fields. When both day fields are
+// restricted, a day matching either one matches, as in standard cron.
+//
+// A "CRON_TZ=<location>" or "TZ=<location>" prefix sets the location the schedule is evaluated in.
+// ParseCron also accepts an options object with the following properties:
+//
+//	CronOptions.Location: the location the schedule is evaluated in, defaults to the location of the time passed to Next and Prev.
+func ParseCron(expression string, opts ...CronOptions) (*CronSchedule, error) {
+	options := parseCronOptions(opts...)
+	schedule := &CronSchedule{expression: expression, location: options.Location}
+
+	spec := strings.TrimSpace(expression)
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, ok := strings.CutPrefix(spec, prefix); ok {
+			name, remainder, _ := strings.Cut(rest, " ")
+			location, err := time.LoadLocation(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCron, err)
+			}
+			schedule.location = location
+			spec = strings.TrimSpace(remainder)
+		}
+	}
+
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+		schedule.hasSeconds = true
+	default:
+		return nil, fmt.Errorf("%w: expected 5 or 6 fields in %q", ErrInvalidCron, expression)
+	}
+	copy(schedule.fields[:], fields)
+
+	if err := schedule.parseFields(); err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrInvalidCron, expression, err)
+	}
+	return schedule, nil
+}
+
+// MustParseCron parses a cron expression like ParseCron.
+// Panics if parsing fails.
+func MustParseCron(expression string, opts ...CronOptions) *CronSchedule {
+	schedule, err := ParseCron(expression, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func (c *CronSchedule) parseFields() error {
+	var err error
+	if c.seconds, err = parseCronField(c.fields[cronSecond], 0, 59, nil); err != nil {
+		return fmt.Errorf("seconds: %w", err)
+	}
+	if c.minutes, err = parseCronField(c.fields[cronMinute], 0, 59, nil); err != nil {
+		return fmt.Errorf("minutes: %w", err)
+	}
+	if c.hours, err = parseCronField(c.fields[cronHour], 0, 23, nil); err != nil {
+		return fmt.Errorf("hours: %w", err)
+	}
+	if c.months, err = parseCronField(c.fields[cronMonth], 1, 12, cronMonthNames); err != nil {
+		return fmt.Errorf("months: %w", err)
+	}
+	if err = c.parseDaysOfMonth(c.fields[cronDayOfMonth]); err != nil {
+		return fmt.Errorf("day of month: %w", err)
+	}
+	if err = c.parseDaysOfWeek(c.fields[cronDayOfWeek]); err != nil {
+		return fmt.Errorf("day of week: %w", err)
+	}
+	return nil
+}
+
+func (c *CronSchedule) parseDaysOfMonth(field string) error {
+	if field == "*" || field == "?" {
+		c.anyDayOfMonth = true
+		return nil
+	}
+
+	var plain []string
+	for _, item := range strings.Split(strings.ToUpper(field), ",") {
+		switch {
+		case item == "L":
+			c.lastDayOffsets = append(c.lastDayOffsets, 0)
+		case item == "LW":
+			c.lastWeekday = true
+		case strings.HasPrefix(item, "L-"):
+			offset, err := parseCronNumber(item[2:], 1, 30, nil)
+			if err != nil {
+				return err
+			}
+			c.lastDayOffsets = append(c.lastDayOffsets, offset)
+		case strings.HasSuffix(item, "W"):
+			day, err := parseCronNumber(strings.TrimSuffix(item, "W"), 1, 31, nil)
+			if err != nil {
+				return err
+			}
+			c.nearestWeekdays = append(c.nearestWeekdays, day)
+		default:
+			plain = append(plain, item)
+		}
+	}
+
+	var err error
+	if len(plain) > 0 {
+		c.daysOfMonth, err = parseCronField(strings.Join(plain, ","), 1, 31, nil)
+	}
+	return err
+}
+
+func (c *CronSchedule) parseDaysOfWeek(field string) error {
+	if field == "*" || field == "?" {
+		c.anyDayOfWeek = true
+		return nil
+	}
+
+	var plain []string
+	for _, item := range strings.Split(strings.ToUpper(field), ",") {
+		switch {
+		case strings.Contains(item, "#"):
+			weekdayText, nthText, _ := strings.Cut(item, "#")
+			weekday, err := parseCronNumber(weekdayText, 0, 7, cronWeekdayNames)
+			if err != nil {
+				return err
+			}
+			nth, err := parseCronNumber(nthText, 1, 5, nil)
+			if err != nil {
+				return err
+			}
+			c.nthWeekdays = append(c.nthWeekdays, [2]int{weekday % 7, nth})
+		case len(item) > 1 && strings.HasSuffix(item, "L"):
+			weekday, err := parseCronNumber(strings.TrimSuffix(item, "L"), 0, 7, cronWeekdayNames)
+			if err != nil {
+				return err
+			}
+			c.lastWeekdaysOf |= 1 << (weekday % 7)
+		default:
+			plain = append(plain, item)
+		}
+	}
+
+	if len(plain) == 0 {
+		return nil
+	}
+	bits, err := parseCronField(strings.Join(plain, ","), 0, 7, cronWeekdayNames)
+	if bits&(1<<7) != 0 {
+		bits = bits&^(1<<7) | 1
+	}
+	c.daysOfWeek = bits
+	return err
+}
+
+// parseCronField parses a list of numbers, ranges and steps into a bit set.
+func parseCronField(field string, minimum, maximum int, names map[string]int) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(strings.ToUpper(field), ",") {
+		rangeText, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = parseCronNumber(stepText, 1, maximum, nil); err != nil {
+				return 0, err
+			}
+		}
+
+		low, high := minimum, maximum
+		switch {
+		case rangeText == "*" || rangeText == "?":
+		case strings.Contains(rangeText, "-"):
+			lowText, highText, _ := strings.Cut(rangeText, "-")
+			var err error
+			if low, err = parseCronNumber(lowText, minimum, maximum, names); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronNumber(highText, minimum, maximum, names); err != nil {
+				return 0, err
+			}
+			if high < low {
+				return 0, fmt.Errorf("range %q is reversed", rangeText)
+			}
+		default:
+			var err error
+			if low, err = parseCronNumber(rangeText, minimum, maximum, names); err != nil {
+				return 0, err
+			}
+			if !hasStep {
+				high = low
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func parseCronNumber(text string, minimum, maximum int, names map[string]int) (int, error) {
+	if value, ok := names[text]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	if value < minimum || value > maximum {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", value, minimum, maximum)
+	}
+	return value, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (c *CronSchedule) String() string {
+	return c.expression
+}
+
+// Next returns the first time after t at which the schedule fires, or the zero time if there is none within
+// a hundred years. The result is in the schedule's location, or t's location if the schedule has none.
+//
+// Across DST transitions the schedule behaves like standard cron: a job with a fixed hour whose local time is skipped
+// by a gap fires once at the end of the gap, and fires only once when its local time occurs twice in an overlap.
+// Jobs with a wildcard hour field, such as "*/15 * * * *", follow the clock and fire in both occurrences of an overlap.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	t = c.in(t)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	from, inclusive := t, false
+
+	for from.Before(limit) {
+		_, offset := from.Zone()
+		segmentStart, segmentEnd := from.ZoneBounds()
+
+		civilFrom := civilOf(from)
+		if !inclusive {
+			civilFrom = civilFrom.Add(time.Second).Truncate(time.Second)
+		}
+		skipBefore := c.repeatedUntil(segmentStart, offset)
+
+		for {
+			civil, ok := c.nextCivil(civilFrom)
+			if !ok {
+				return time.Time{}
+			}
+			instant := fromCivil(civil, offset, from.Location())
+			if !segmentEnd.IsZero() && !instant.Before(segmentEnd) {
+				break
+			}
+			if civil.Before(skipBefore) {
+				civilFrom = civil.Add(time.Second)
+				continue
+			}
+			return instant
+		}
+
+		if c.firesInGap(segmentEnd, offset) {
+			return segmentEnd
+		}
+		from, inclusive = segmentEnd, true
+	}
+	return time.Time{}
+}
+
+// Prev returns the last time before t at which the schedule fired, or the zero time if there is none within
+// a hundred years. DST transitions are handled as in Next.
+func (c *CronSchedule) Prev(t time.Time) time.Time {
+	t = c.in(t)
+	limit := t.AddDate(-cronSearchYears, 0, 0)
+	from := t
+
+	for from.After(limit) {
+		_, offset := from.Zone()
+		segmentStart, _ := from.ZoneBounds()
+		skipBefore := c.repeatedUntil(segmentStart, offset)
+
+		civilFrom := civilOf(from)
+		if civilFrom.Equal(civilFrom.Truncate(time.Second)) {
+			civilFrom = civilFrom.Add(-time.Second)
+		} else {
+			civilFrom = civilFrom.Truncate(time.Second)
+		}
+
+		for {
+			civil, ok := c.prevCivil(civilFrom)
+			if !ok {
+				return time.Time{}
+			}
+			instant := fromCivil(civil, offset, from.Location())
+			if !segmentStart.IsZero() && instant.Before(segmentStart) {
+				break
+			}
+			if civil.Before(skipBefore) {
+				// repeated local times only fire in their first occurrence, in the previous segment.
+				break
+			}
+			return instant
+		}
+
+		if segmentStart.IsZero() {
+			return time.Time{}
+		}
+		previous := segmentStart.Add(-time.Nanosecond)
+		if _, previousOffset := previous.Zone(); c.firesInGap(segmentStart, previousOffset) && segmentStart.Before(t) {
+			return segmentStart
+		}
+		from = previous
+	}
+	return time.Time{}
+}
+
+func (c *CronSchedule) in(t time.Time) time.Time {
+	if c.location != nil {
+		return t.In(c.location)
+	}
+	return t
+}
+
+// fixedHour reports whether the hour field is restricted, which decides how DST transitions are handled.
+func (c *CronSchedule) fixedHour() bool {
+	return c.hours != 1<<24-1
+}
+
+// repeatedUntil returns the civil time before which local times of the segment starting at segmentStart already
+// occurred in the previous segment, for fixed-hour schedules after a DST fall-back. It returns the zero time otherwise.
+func (c *CronSchedule) repeatedUntil(segmentStart time.Time, offset int) time.Time {
+	if segmentStart.IsZero() || !c.fixedHour() {
+		return time.Time{}
+	}
+	_, previousOffset := segmentStart.Add(-time.Nanosecond).Zone()
+	if previousOffset <= offset {
+		return time.Time{}
+	}
+	return civilAt(segmentStart, previousOffset)
+}
+
+// firesInGap reports whether a fixed-hour schedule matches a local time skipped by a DST gap starting at transition.
+func (c *CronSchedule) firesInGap(transition time.Time, offsetBefore int) bool {
+	if transition.IsZero() || !c.fixedHour() {
+		return false
+	}
+	_, offsetAfter := transition.Zone()
+	if offsetAfter <= offsetBefore {
+		return false
+	}
+
+	gapStart := civilAt(transition, offsetBefore)
+	civil, ok := c.nextCivil(gapStart)
+	return ok && civil.Before(civilAt(transition, offsetAfter))
+}
+
+// nextCivil returns the first matching wall clock time at or after from. Wall clock times are represented in UTC.
+func (c *CronSchedule) nextCivil(from time.Time) (time.Time, bool) {
+	t := from
+	limit := from.Year() + cronSearchYears
+
+	for t.Year() <= limit {
+		year, month, day := t.Date()
+		switch {
+		case c.months&(1<<month) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.matchesDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+		case c.hours&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case c.minutes&(1<<t.Minute()) == 0:
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		case c.seconds&(1<<t.Second()) == 0:
+			t = t.Add(time.Second)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// prevCivil returns the last matching wall clock time at or before from. Wall clock times are represented in UTC.
+func (c *CronSchedule) prevCivil(from time.Time) (time.Time, bool) {
+	t := from
+	limit := from.Year() - cronSearchYears
+
+	for t.Year() >= limit {
+		year, month, day := t.Date()
+		switch {
+		case c.months&(1<<month) == 0:
+			t = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		case !c.matchesDay(t):
+			t = time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		case c.hours&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(-time.Second)
+		case c.minutes&(1<<t.Minute()) == 0:
+			t = t.Truncate(time.Minute).Add(-time.Second)
+		case c.seconds&(1<<t.Second()) == 0:
+			t = t.Add(-time.Second)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return c.matchesDayOfWeek(t)
+	case c.anyDayOfWeek:
+		return c.matchesDayOfMonth(t)
+	default:
+		return c.matchesDayOfMonth(t) || c.matchesDayOfWeek(t)
+	}
+}
+
+func (c *CronSchedule) matchesDayOfMonth(t time.Time) bool {
+	day := t.Day()
+	if c.daysOfMonth&(1<<day) != 0 {
+		return true
+	}
+
+	daysInMonth := DaysInMonth(t)
+	for _, offset := range c.lastDayOffsets {
+		if day == daysInMonth-offset {
+			return true
+		}
+	}
+	for _, target := range c.nearestWeekdays {
+		if target <= daysInMonth && day == nearestWeekday(t, target) {
+			return true
+		}
+	}
+	return c.lastWeekday && day == nearestWeekday(t, daysInMonth)
+}
+
+// nearestWeekday returns the weekday (Monday to Friday) nearest to the given day of t's month, without leaving it.
+func nearestWeekday(t time.Time, day int) int {
+	daysInMonth := DaysInMonth(t)
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return 3
+		}
+		return day - 1
+	case time.Sunday:
+		if day == daysInMonth {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
+
+func (c *CronSchedule) matchesDayOfWeek(t time.Time) bool {
+	weekday := t.Weekday()
+	if c.daysOfWeek&(1<<weekday) != 0 {
+		return true
+	}
+	if c.lastWeekdaysOf&(1<<weekday) != 0 && t.Day()+7 > DaysInMonth(t) {
+		return true
+	}
+	for _, nth := range c.nthWeekdays {
+		if int(weekday) == nth[0] && (t.Day()-1)/7+1 == nth[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// civilOf returns the wall clock time of t, represented in UTC.
+func civilOf(t time.Time) time.Time {
+	_, offset := t.Zone()
+	return civilAt(t, offset)
+}
+
+func civilAt(t time.Time, offset int) time.Time {
+	return t.UTC().Add(time.Duration(offset) * time.Second)
+}
+
+func fromCivil(civil time.Time, offset int, location *time.Location) time.Time {
+	return civil.Add(-time.Duration(offset) * time.Second).In(location)
+}
+
+var cronOrdinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+// Describe returns a human-readable English description of the schedule, such as
+// "every 15 minutes, hours 9 through 17 on Monday through Friday" or "at 09:00 on the last Friday of the month".
+func (c *CronSchedule) Describe() string {
+	parts := []string{c.describeTime()}
+
+	if days := c.describeDays(); days != "" {
+		parts = append(parts, days)
+	}
+	if c.fields[cronMonth] != "*" {
+		parts = append(parts, "in "+describeCronItems(c.fields[cronMonth], "", "month", cronMonthName))
+	}
+	if c.location != nil {
+		parts = append(parts, "("+c.location.String()+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c *CronSchedule) describeTime() string {
+	second, minute, hour := c.fields[cronSecond], c.fields[cronMinute], c.fields[cronHour]
+
+	if isPlainCronList(second) && isPlainCronList(minute) && isPlainCronList(hour) {
+		seconds, minutes, hours := strings.Split(second, ","), strings.Split(minute, ","), strings.Split(hour, ",")
+		if len(seconds)*len(minutes)*len(hours) <= 6 {
+			var times []string
+			for _, h := range hours {
+				for _, m := range minutes {
+					for _, s := range seconds {
+						times = append(times, formatCronClock(h, m, s, c.hasSeconds && second != "0"))
+					}
+				}
+			}
+			return "at " + joinWithAnd(times)
+		}
+	}
+
+	var phrases []string
+	if c.hasSeconds && second != "0" {
+		phrases = append(phrases, describeCronItems(second, "second", "seconds", nil))
+	}
+	if minute != "*" || len(phrases) == 0 {
+		phrases = append(phrases, describeCronItems(minute, "minute", "minutes", nil))
+	}
+	switch {
+	case hour != "*":
+		phrases = append(phrases, describeCronItems(hour, "hour", "hours", nil))
+	case !strings.HasPrefix(phrases[len(phrases)-1], "every"):
+		phrases[len(phrases)-1] += " of every hour"
+	}
+
+	if !strings.HasPrefix(phrases[0], "every") {
+		phrases[0] = "at " + phrases[0]
+	}
+	return strings.Join(phrases, ", ")
+}
+
+func (c *CronSchedule) describeDays() string {
+	var phrases []string
+
+	if !c.anyDayOfMonth {
+		var special, plain []string
+		for _, item := range strings.Split(strings.ToUpper(c.fields[cronDayOfMonth]), ",") {
+			switch {
+			case item == "L":
+				special = append(special, "the last day")
+			case item == "LW":
+				special = append(special, "the last weekday")
+			case strings.HasPrefix(item, "L-"):
+				special = append(special, item[2:]+" days before the last day")
+			case strings.HasSuffix(item, "W"):
+				special = append(special, "the weekday nearest day "+strings.TrimSuffix(item, "W"))
+			default:
+				plain = append(plain, item)
+			}
+		}
+		if len(plain) > 0 {
+			special = append([]string{describeCronItems(strings.Join(plain, ","), "day", "days", nil)}, special...)
+		}
+		phrases = append(phrases, "on "+joinWithAnd(special)+" of the month")
+	}
+
+	if !c.anyDayOfWeek {
+		var special, plain []string
+		for _, item := range strings.Split(strings.ToUpper(c.fields[cronDayOfWeek]), ",") {
+			switch {
+			case strings.Contains(item, "#"):
+				weekday, nth, _ := strings.Cut(item, "#")
+				index, err := strconv.Atoi(nth)
+				if err != nil || index >= len(cronOrdinals) {
+					index = 0
+				}
+				special = append(special, "the "+cronOrdinals[index]+" "+cronWeekdayName(weekday)+" of the month")
+			case len(item) > 1 && strings.HasSuffix(item, "L"):
+				special = append(special, "the last "+cronWeekdayName(strings.TrimSuffix(item, "L"))+" of the month")
+			default:
+				plain = append(plain, item)
+			}
+		}
+		if len(plain) > 0 {
+			special = append([]string{describeCronItems(strings.Join(plain, ","), "", "days", cronWeekdayName)}, special...)
+		}
+		phrases = append(phrases, "on "+joinWithAnd(special))
+	}
+
+	return strings.Join(phrases, " or ")
+}
+
+// describeCronItems describes a list of values, ranges and steps. Plain values are prefixed with the singular or
+// plural noun unless singular is empty; format renders a single value.
+func describeCronItems(field, singular, plural string, format func(string) string) string {
+	if format == nil {
+		format = func(value string) string { return value }
+	}
+
+	items := strings.Split(strings.ToUpper(field), ",")
+	phrases := make([]string, 0, len(items))
+	plainValues := 0
+
+	for _, item := range items {
+		rangeText, step, hasStep := strings.Cut(item, "/")
+		low, high, isRange := strings.Cut(rangeText, "-")
+
+		var phrase string
+		switch {
+		case rangeText == "*" || rangeText == "?":
+			phrase = "every " + cmp.Or(singular, strings.TrimSuffix(plural, "s"))
+		case isRange:
+			phrase = format(low) + " through " + format(high)
+		default:
+			phrase = format(rangeText)
+		}
+
+		if hasStep {
+			every := "every " + step + " " + plural
+			if step == "1" {
+				every = "every " + cmp.Or(singular, strings.TrimSuffix(plural, "s"))
+			}
+			switch {
+			case rangeText == "*" || rangeText == "?":
+				phrase = every
+			case isRange:
+				phrase = every + " from " + phrase
+			default:
+				phrase = every + " starting at " + phrase
+			}
+		} else if rangeText != "*" && rangeText != "?" {
+			plainValues++
+			if isRange {
+				plainValues++
+			}
+		}
+		phrases = append(phrases, phrase)
+	}
+
+	if singular == "" || plainValues == 0 {
+		return joinWithAnd(phrases)
+	}
+	if plainValues == 1 && len(items) == 1 {
+		return singular + " " + joinWithAnd(phrases)
+	}
+	return plural + " " + joinWithAnd(phrases)
+}
+
+func isPlainCronList(field string) bool {
+	return strings.Trim(field, "0123456789,") == ""
+}
+
+func formatCronClock(hour, minute, second string, withSeconds bool) string {
+	h, hourErr := strconv.Atoi(hour)
+	m, minuteErr := strconv.Atoi(minute)
+	s, secondErr := strconv.Atoi(second)
+	if hourErr != nil || minuteErr != nil || secondErr != nil {
+		return hour + ":" + minute
+	}
+	if withSeconds {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
+
+func cronMonthName(value string) string {
+	month, err := parseCronNumber(value, 1, 12, cronMonthNames)
+	if err != nil {
+		return value
+	}
+	return time.Month(month).String()
+}
+
+func cronWeekdayName(value string) string {
+	weekday, err := parseCronNumber(value, 0, 7, cronWeekdayNames)
+	if err != nil {
+		return value
+	}
+	return time.Weekday(weekday % 7).String()
+}
+
+func joinWithAnd(values []string) string {
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return values[0]
+	default:
+		return strings.Join(values[:len(values)-1], ", ") + " and " + values[len(values)-1]
+	}
+}
@@ -0,0 +1,236 @@
+package dateutils_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const cronLayout = "2006-01-02 15:04:05 MST"
+
+func nextRuns(schedule *dateutils.CronSchedule, from time.Time, count int) []string {
+	result := make([]string, 0, count)
+	for range count {
+		from = schedule.Next(from)
+		if from.IsZero() {
+			break
+		}
+		result = append(result, from.Format(cronLayout))
+	}
+	return result
+}
+
+func TestParseCron(t *testing.T) {
+	testCases := []struct {
+		expression string
+		valid      bool
+	}{
+		{"* * * * *", true},
+		{"*/15 9-17 * * MON-FRI", true},
+		{"0 0 1 JAN *", true},
+		{"30 0 0 L * ?", true},
+		{"0 0 L-3 * *", true},
+		{"0 0 15W * *", true},
+		{"0 0 LW * *", true},
+		{"0 0 * * 5L", true},
+		{"0 0 * * MON#2", true},
+		{"@hourly", true},
+		{"@Daily", true},
+		{"CRON_TZ=America/New_York 0 9 * * *", true},
+		{"", false},
+		{"* * * *", false},
+		{"* * * * * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"* * * FOO *", false},
+		{"*/0 * * * *", false},
+		{"5-1 * * * *", false},
+		{"0 0 * * MON#6", false},
+		{"@every", false},
+		{"CRON_TZ=Nowhere/Nothing * * * * *", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression, func(t *testing.T) {
+			schedule, err := dateutils.ParseCron(testCase.expression)
+			if testCase.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expression, schedule.String())
+			} else {
+				assert.True(t, errors.Is(err, dateutils.ErrInvalidCron))
+			}
+		})
+	}
+
+	assert.Panics(t, func() { dateutils.MustParseCron("not cron") })
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	from := time.Date(2024, time.January, 30, 10, 17, 30, 0, time.UTC)
+
+	testCases := []struct {
+		expression string
+		expected   []string
+	}{
+		{"* * * * *", []string{"2024-01-30 10:18:00 UTC", "2024-01-30 10:19:00 UTC"}},
+		{"*/20 * * * *", []string{"2024-01-30 10:20:00 UTC", "2024-01-30 10:40:00 UTC", "2024-01-30 11:00:00 UTC"}},
+		{"@hourly", []string{"2024-01-30 11:00:00 UTC", "2024-01-30 12:00:00 UTC"}},
+		{"@monthly", []string{"2024-02-01 00:00:00 UTC", "2024-03-01 00:00:00 UTC"}},
+		{"0 9 * * MON-FRI", []string{"2024-01-31 09:00:00 UTC", "2024-02-01 09:00:00 UTC", "2024-02-02 09:00:00 UTC", "2024-02-05 09:00:00 UTC"}},
+		{"0 12 * FEB,MAR sun", []string{"2024-02-04 12:00:00 UTC", "2024-02-11 12:00:00 UTC"}},
+		{"0 0 L * *", []string{"2024-01-31 00:00:00 UTC", "2024-02-29 00:00:00 UTC", "2024-03-31 00:00:00 UTC"}},
+		{"0 0 L-1 * *", []string{"2024-02-28 00:00:00 UTC", "2024-03-30 00:00:00 UTC"}},
+		{"0 0 LW * *", []string{"2024-01-31 00:00:00 UTC", "2024-02-29 00:00:00 UTC", "2024-03-29 00:00:00 UTC"}},
+		{"0 0 1W * *", []string{"2024-02-01 00:00:00 UTC", "2024-03-01 00:00:00 UTC", "2024-04-01 00:00:00 UTC", "2024-05-01 00:00:00 UTC", "2024-06-03 00:00:00 UTC"}},
+		{"0 0 15W * *", []string{"2024-02-15 00:00:00 UTC", "2024-03-15 00:00:00 UTC", "2024-04-15 00:00:00 UTC", "2024-05-15 00:00:00 UTC", "2024-06-14 00:00:00 UTC"}},
+		{"0 0 * * 5L", []string{"2024-02-23 00:00:00 UTC", "2024-03-29 00:00:00 UTC"}},
+		{"0 0 * * MON#2", []string{"2024-02-12 00:00:00 UTC", "2024-03-11 00:00:00 UTC"}},
+		{"0 0 13 * FRI", []string{"2024-02-02 00:00:00 UTC", "2024-02-09 00:00:00 UTC", "2024-02-13 00:00:00 UTC", "2024-02-16 00:00:00 UTC"}},
+		{"0 0 29 2 *", []string{"2024-02-29 00:00:00 UTC", "2028-02-29 00:00:00 UTC"}},
+		{"*/15 * * * * *", []string{"2024-01-30 10:17:45 UTC", "2024-01-30 10:18:00 UTC"}},
+		{"0 0 30 2 *", []string{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression, func(t *testing.T) {
+			schedule := dateutils.MustParseCron(testCase.expression)
+			assert.Equal(t, testCase.expected, nextRuns(schedule, from, len(testCase.expected)+1)[:len(testCase.expected)])
+		})
+	}
+}
+
+func TestCronSchedulePrev(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		expression string
+		expected   string
+	}{
+		{"* * * * *", "2024-02-29 23:59:00 UTC"},
+		{"@monthly", "2024-02-01 00:00:00 UTC"},
+		{"0 0 L * *", "2024-02-29 00:00:00 UTC"},
+		{"0 9 * * MON#1", "2024-02-05 09:00:00 UTC"},
+		{"30 0 0 1 1 *", "2024-01-01 00:00:30 UTC"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression, func(t *testing.T) {
+			schedule := dateutils.MustParseCron(testCase.expression)
+			assert.Equal(t, testCase.expected, schedule.Prev(from).Format(cronLayout))
+		})
+	}
+
+	assert.True(t, dateutils.MustParseCron("0 0 30 2 *").Prev(from).IsZero())
+}
+
+func TestCronScheduleRoundTrip(t *testing.T) {
+	schedule := dateutils.MustParseCron("*/7 9-17 * * MON-FRI")
+	current := schedule.Next(time.Date(2024, time.May, 3, 16, 50, 0, 0, time.UTC))
+
+	for range 50 {
+		next := schedule.Next(current)
+		assert.True(t, next.After(current))
+		assert.Equal(t, current, schedule.Prev(next))
+		current = next
+	}
+}
+
+func TestCronScheduleDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	options := dateutils.CronOptions{Location: newYork}
+
+	testCases := []struct {
+		name       string
+		expression string
+		from       time.Time
+		expected   []string
+	}{
+		{
+			"fixed hour in spring gap fires at the end of the gap", "30 2 * * *",
+			time.Date(2024, time.March, 9, 12, 0, 0, 0, newYork),
+			[]string{"2024-03-10 03:00:00 EDT", "2024-03-11 02:30:00 EDT"},
+		},
+		{
+			"wildcard hour skips the spring gap", "*/30 * * * *",
+			time.Date(2024, time.March, 10, 1, 15, 0, 0, newYork),
+			[]string{"2024-03-10 01:30:00 EST", "2024-03-10 03:00:00 EDT", "2024-03-10 03:30:00 EDT"},
+		},
+		{
+			"fixed hour in fall overlap fires once", "30 1 * * *",
+			time.Date(2024, time.November, 2, 12, 0, 0, 0, newYork),
+			[]string{"2024-11-03 01:30:00 EDT", "2024-11-04 01:30:00 EST"},
+		},
+		{
+			"wildcard hour fires in both occurrences of the overlap", "*/30 * * * *",
+			time.Date(2024, time.November, 3, 0, 45, 0, 0, newYork),
+			[]string{"2024-11-03 01:00:00 EDT", "2024-11-03 01:30:00 EDT", "2024-11-03 01:00:00 EST", "2024-11-03 01:30:00 EST", "2024-11-03 02:00:00 EST"},
+		},
+		{
+			"location from prefix", "CRON_TZ=America/New_York 0 9 * * *",
+			time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC),
+			[]string{"2024-03-10 09:00:00 EDT", "2024-03-11 09:00:00 EDT"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			schedule, err := dateutils.ParseCron(testCase.expression, options)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, nextRuns(schedule, testCase.from, len(testCase.expected)))
+		})
+	}
+
+	t.Run("prev across overlap", func(t *testing.T) {
+		schedule := dateutils.MustParseCron("30 1 * * *", options)
+		from := time.Date(2024, time.November, 3, 3, 0, 0, 0, newYork)
+		assert.Equal(t, "2024-11-03 01:30:00 EDT", schedule.Prev(from).Format(cronLayout))
+	})
+
+	t.Run("prev across gap", func(t *testing.T) {
+		schedule := dateutils.MustParseCron("30 2 * * *", options)
+		from := time.Date(2024, time.March, 10, 12, 0, 0, 0, newYork)
+		assert.Equal(t, "2024-03-10 03:00:00 EDT", schedule.Prev(from).Format(cronLayout))
+	})
+}
+
+func TestCronScheduleDescribe(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expected   string
+	}{
+		{"* * * * *", "every minute"},
+		{"* * * * * *", "every second"},
+		{"*/15 * * * *", "every 15 minutes"},
+		{"30 * * * *", "at minute 30 of every hour"},
+		{"0 */2 * * *", "at minute 0, every 2 hours"},
+		{"0 9 * * *", "at 09:00"},
+		{"0 9,17 * * *", "at 09:00 and 17:00"},
+		{"30 0 9 * * *", "at 09:00:30"},
+		{"*/15 9-17 * * MON-FRI", "every 15 minutes, hours 9 through 17 on Monday through Friday"},
+		{"@yearly", "at 00:00 on day 1 of the month in January"},
+		{"0 0 1,15 * *", "at 00:00 on days 1 and 15 of the month"},
+		{"0 0 L * *", "at 00:00 on the last day of the month"},
+		{"0 0 L-2 * *", "at 00:00 on 2 days before the last day of the month"},
+		{"0 9 15W * *", "at 09:00 on the weekday nearest day 15 of the month"},
+		{"0 9 * * 5L", "at 09:00 on the last Friday of the month"},
+		{"0 9 * * MON#2", "at 09:00 on the second Monday of the month"},
+		{"0 9 13 * FRI", "at 09:00 on day 13 of the month or on Friday"},
+		{"0 9 * JUN-AUG SAT,SUN", "at 09:00 on Saturday and Sunday in June through August"},
+		{"CRON_TZ=Europe/Berlin 0 9 * * *", "at 09:00 (Europe/Berlin)"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.MustParseCron(testCase.expression).Describe())
+		})
+	}
+}
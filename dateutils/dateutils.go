@@ -3,11 +3,13 @@ package dateutils
 import "time"
 
 // Floor - takes a datetime and return a datetime from the same day at 00:00:00 (UTC).
+// Use TruncateTo to keep the datetime's location.
 func Floor(t time.Time) time.Time {
 	return t.UTC().Truncate(time.Hour * 24)
 }
 
 // Ceil - takes a datetime and return a datetime from the same day at 23:59:59 (UTC).
+// Use CeilTo to keep the datetime's location and nanosecond precision.
 func Ceil(t time.Time) time.Time {
 	// add 24 hours so that we are dealing with tomorrow's datetime
 	// Floor
@@ -34,6 +36,7 @@ func AfterOrEqual(milestone time.Time, date time.Time) bool {
 }
 
 // Overlap - returns true if two date intervals overlap.
+// Use Interval for ranges with open bounds or for intersections, unions and differences.
 func Overlap(start1 time.Time, end1 time.Time, start2 time.Time, end2 time.Time) bool {
 	beforeOrEqual := func(milestone, date time.Time) bool {
 		return date.UTC().Before(milestone) || date.UTC().Equal(milestone)
@@ -49,11 +52,13 @@ func Overlap(start1 time.Time, end1 time.Time, start2 time.Time, end2 time.Time)
 
 // GetFirstDayOfMonth returns the first day of the current month at 00:00:00 in the local timezone.
 func GetFirstDayOfMonth() time.Time {
-	now := time.Now()
-	currentYear, currentMonth, _ := now.Date()
-	currentLocation := now.Location()
+	return GetFirstDayOfMonthWithClock(RealClock{})
+}
 
-	return time.Date(currentYear, currentMonth, 1, 0, 0, 0, 0, currentLocation)
+// GetFirstDayOfMonthWithClock returns the first day of the clock's current month at 00:00:00 in the location of
+// the clock's time.
+func GetFirstDayOfMonthWithClock(clock Clock) time.Time {
+	return GetFirstDayOfMonthFor(clock.Now())
 }
 
 // GetFirstDayOfMonthFor returns the first day of the month for the given date at 00:00:00 in the date's timezone.
@@ -66,9 +71,13 @@ func GetFirstDayOfMonthFor(date time.Time) time.Time {
 
 // GetLastDayOfMonth returns the last day of the current month at 23:59:59 in the local timezone.
 func GetLastDayOfMonth() time.Time {
-	firstDay := GetFirstDayOfMonth()
-	// Add one month and subtract one second
-	return firstDay.AddDate(0, 1, 0).Add(-time.Second)
+	return GetLastDayOfMonthWithClock(RealClock{})
+}
+
+// GetLastDayOfMonthWithClock returns the last day of the clock's current month at 23:59:59 in the location of
+// the clock's time.
+func GetLastDayOfMonthWithClock(clock Clock) time.Time {
+	return GetLastDayOfMonthFor(clock.Now())
 }
 
 // GetLastDayOfMonthFor returns the last day of the month for the given date at 23:59:59 in the date's timezone.
@@ -79,7 +88,7 @@ func GetLastDayOfMonthFor(date time.Time) time.Time {
 }
 
 // ParseDate parses a date string in RFC3339 format.
-// If parsing fails, returns the fallback value.
+// If parsing fails, returns the fallback value. Use ParseDateAny to detect other layouts and epoch values.
 func ParseDate(date string, fallback time.Time) time.Time {
 	parsedDate, parseErr := time.Parse(time.RFC3339, date)
 	if parseErr != nil {
@@ -121,7 +130,7 @@ func MustParseDateWithLayout(date string, layout string) time.Time {
 // StartOfDay returns the start of the day (00:00:00) for the given time in its timezone.
 func StartOfDay(t time.Time) time.Time {
 	year, month, day := t.Date()
-	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	return startOfDate(year, month, day, t.Location())
 }
 
 // EndOfDay returns the end of the day (23:59:59.999999999) for the given time in its timezone.
@@ -131,23 +140,47 @@ func EndOfDay(t time.Time) time.Time {
 }
 
 // StartOfWeek returns the start of the week (Sunday at 00:00:00) for the given time.
+// Use StartOfWeekOn for weeks starting on another day.
 func StartOfWeek(t time.Time) time.Time {
-	weekday := int(t.Weekday())
-	return StartOfDay(t.AddDate(0, 0, -weekday))
+	return StartOfWeekOn(t, time.Sunday)
 }
 
 // EndOfWeek returns the end of the week (Saturday at 23:59:59.999999999) for the given time.
+// Use EndOfWeekOn for weeks starting on another day.
 func EndOfWeek(t time.Time) time.Time {
-	weekday := int(t.Weekday())
-	daysUntilSaturday := 6 - weekday
-	return EndOfDay(t.AddDate(0, 0, daysUntilSaturday))
+	return EndOfWeekOn(t, time.Sunday)
+}
+
+// StartOfQuarter returns the first instant of the calendar quarter containing t, in t's location.
+func StartOfQuarter(t time.Time) time.Time {
+	return TruncateTo(t, Quarter)
+}
+
+// EndOfQuarter returns the last instant (to the nanosecond) of the calendar quarter containing t, in t's location.
+func EndOfQuarter(t time.Time) time.Time {
+	return CeilTo(t, Quarter)
+}
+
+// StartOfYear returns the first instant of the calendar year containing t, in t's location.
+func StartOfYear(t time.Time) time.Time {
+	return TruncateTo(t, Year)
 }
 
-// DaysBetween returns the number of days between two dates.
+// EndOfYear returns the last instant (to the nanosecond) of the calendar year containing t, in t's location.
+func EndOfYear(t time.Time) time.Time {
+	return CeilTo(t, Year)
+}
+
+// QuarterOf returns the calendar quarter of t, from 1 to 4. Use FiscalCalendar for fiscal quarters.
+func QuarterOf(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
+
+// DaysBetween returns the number of whole days between two dates, counted on the wall clock in start's location, so
+// a day that is 23 or 25 hours long across a DST change counts as one day.
 // The result is negative if end is before start.
 func DaysBetween(start, end time.Time) int {
-	duration := end.Sub(start)
-	return int(duration.Hours() / 24)
+	return wholeDays(start, end.In(start.Location()))
 }
 
 // IsWeekend returns true if the given time is on a weekend (Saturday or Sunday).
@@ -163,50 +196,30 @@ func IsWeekday(t time.Time) bool {
 
 // AddBusinessDays adds the specified number of business days to the given time.
 // Business days are Monday through Friday, skipping weekends.
-// Negative values subtract business days.
+// Negative values subtract business days. Use a Calendar to also skip holidays.
 func AddBusinessDays(t time.Time, days int) time.Time {
-	if days == 0 {
-		return t
-	}
-
-	direction := 1
-	if days < 0 {
-		direction = -1
-		days = -days
-	}
-
-	result := t
-	for i := 0; i < days; {
-		result = result.AddDate(0, 0, direction)
-		if IsWeekday(result) {
-			i++
-		}
-	}
-	return result
+	return weekendCalendar.AddBusinessDays(t, days)
 }
 
 // Age calculates the age in years from the given birthdate to now.
 func Age(birthdate time.Time) int {
-	return AgeAt(birthdate, time.Now())
+	return AgeWithClock(birthdate, RealClock{})
+}
+
+// AgeWithClock calculates the age in years from the given birthdate to the clock's current time.
+func AgeWithClock(birthdate time.Time, clock Clock) int {
+	return AgeAt(birthdate, clock.Now())
 }
 
 // AgeAt calculates the age in years from the given birthdate to the specified date.
+// See Date.AgeAt for birthdates without a time zone.
 func AgeAt(birthdate, at time.Time) int {
-	age := at.Year() - birthdate.Year()
-	if at.Month() < birthdate.Month() ||
-		(at.Month() == birthdate.Month() && at.Day() < birthdate.Day()) {
-		age--
-	}
-	return age
+	return DateOf(birthdate).AgeAt(DateOf(at))
 }
 
 // DaysInMonth returns the number of days in the month of the given time.
 func DaysInMonth(t time.Time) int {
-	// Get the first day of next month, then subtract one day
-	year, month, _ := t.Date()
-	firstOfNextMonth := time.Date(year, month+1, 1, 0, 0, 0, 0, t.Location())
-	lastOfThisMonth := firstOfNextMonth.AddDate(0, 0, -1)
-	return lastOfThisMonth.Day()
+	return DateOf(t).DaysInMonth()
 }
 
 // IsSameDay returns true if both times are on the same day (same year, month, and day).
@@ -247,6 +247,24 @@ func TestEndOfWeek(t *testing.T) {
 	assert.Equal(t, 59, result.Second())
 }
 
+func TestStartAndEndOfQuarter(t *testing.T) {
+	// Thursday, August 15, 2024
+	august := time.Date(2024, 8, 15, 14, 30, 45, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), dateutils.StartOfQuarter(august))
+	assert.Equal(t, time.Date(2024, 9, 30, 23, 59, 59, 999999999, time.UTC), dateutils.EndOfQuarter(august))
+	assert.Equal(t, 3, dateutils.QuarterOf(august))
+	assert.Equal(t, 1, dateutils.QuarterOf(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 4, dateutils.QuarterOf(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestStartAndEndOfYear(t *testing.T) {
+	august := time.Date(2024, 8, 15, 14, 30, 45, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), dateutils.StartOfYear(august))
+	assert.Equal(t, time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC), dateutils.EndOfYear(august))
+}
+
 func TestDaysBetween(t *testing.T) {
 	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
@@ -257,6 +275,15 @@ func TestDaysBetween(t *testing.T) {
 
 	// Same day
 	assert.Equal(t, 0, dateutils.DaysBetween(start, start))
+
+	// Partial day
+	assert.Equal(t, 0, dateutils.DaysBetween(start, end.Add(-time.Hour).AddDate(0, 0, -8)))
+
+	// The 23-hour day of a DST change counts as a day
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	beforeChange := time.Date(2024, 3, 9, 12, 0, 0, 0, newYork)
+	assert.Equal(t, 1, dateutils.DaysBetween(beforeChange, beforeChange.AddDate(0, 0, 1)))
 }
 
 func TestIsWeekend(t *testing.T) {
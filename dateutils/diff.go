@@ -0,0 +1,92 @@
+package dateutils
+
+import "time"
+
+// DiffOptions - calendar difference options.
+type DiffOptions struct {
+	Location *time.Location
+	Weeks    bool
+}
+
+func parseDiffOptions(opts ...DiffOptions) DiffOptions {
+	options := DiffOptions{}
+
+	for _, opt := range opts {
+		if opt.Location != nil {
+			options.Location = opt.Location
+		}
+		if opt.Weeks {
+			options.Weeks = true
+		}
+	}
+
+	return options
+}
+
+// Diff returns the difference from start to end in calendar components, such as 2 years, 3 months, 4 days and
+// 5 hours. Whole months are counted first, clamping to the end of the month like Period.AddTo, then whole days on the
+// wall clock, so a day across a DST change counts as one day. The rest is exact elapsed time, in hours, minutes,
+// seconds and nanoseconds. If end is before start, every component is zero or negative.
+// The result added back to start gives end exactly: Diff(start, end).AddTo(start) equals end. With a Location,
+// use start.In(location) as the base.
+// Diff also accepts an options object with the following properties:
+//
+//	DiffOptions.Location: the location in which calendar components are counted, defaults to start's location.
+//	DiffOptions.Weeks: express whole weeks as Weeks instead of Days, defaults to false.
+func Diff(start, end time.Time, opts ...DiffOptions) Period {
+	options := parseDiffOptions(opts...)
+	if options.Location != nil {
+		start = start.In(options.Location)
+	}
+	end = end.In(start.Location())
+
+	months := wholeMonths(start, end)
+	afterMonths := addMonths(start, months, MonthEndClamp)
+	days := wholeDays(afterMonths, end)
+	exact := end.Sub(afterMonths.AddDate(0, 0, days))
+
+	period := PeriodFromDuration(exact)
+	period.Years, period.Months, period.Days = months/12, months%12, days
+	if options.Weeks {
+		period.Weeks, period.Days = days/7, days%7
+	}
+	return period
+}
+
+// MonthsBetween returns the number of whole months from start to end in start's location, negative if end is before
+// start. Months are added with clamping to the end of the month, so January 31st to February 29th is one month.
+func MonthsBetween(start, end time.Time) int {
+	return wholeMonths(start, end.In(start.Location()))
+}
+
+// WeeksBetween returns the number of whole weeks from start to end in start's location, negative if end is before
+// start. See DaysBetween.
+func WeeksBetween(start, end time.Time) int {
+	return DaysBetween(start, end) / 7
+}
+
+// wholeMonths returns the largest number of months that can be added to start, with clamping, without passing end.
+func wholeMonths(start, end time.Time) int {
+	months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+	candidate := addMonths(start, months, MonthEndClamp)
+	switch {
+	case months > 0 && candidate.After(end):
+		months--
+	case months < 0 && candidate.Before(end):
+		months++
+	}
+	return months
+}
+
+// wholeDays returns the largest number of days that can be added to start on the wall clock without passing end.
+func wholeDays(start, end time.Time) int {
+	days := int(dayNumber(end) - dayNumber(start))
+	candidate := start.AddDate(0, 0, days)
+	switch {
+	case days > 0 && candidate.After(end):
+		days--
+	case days < 0 && candidate.Before(end):
+		days++
+	}
+	return days
+}
@@ -0,0 +1,116 @@
+package dateutils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	for _, testCase := range []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		options  dateutils.DiffOptions
+		expected dateutils.Period
+	}{
+		{
+			name:     "Components",
+			start:    time.Date(2021, 1, 10, 8, 0, 0, 0, time.UTC),
+			end:      time.Date(2023, 4, 14, 13, 30, 15, 0, time.UTC),
+			expected: dateutils.Period{Years: 2, Months: 3, Days: 4, Hours: 5, Minutes: 30, Seconds: 15},
+		},
+		{
+			name:     "Same time",
+			start:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: dateutils.Period{},
+		},
+		{
+			name:     "Partial month",
+			start:    time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			expected: dateutils.Period{Days: 28, Hours: 23},
+		},
+		{
+			name:     "Month end clamped",
+			start:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			expected: dateutils.Period{Months: 1},
+		},
+		{
+			name:     "Negative",
+			start:    time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 2, 28, 6, 0, 0, 0, time.UTC),
+			expected: dateutils.Period{Months: -1, Days: -1, Hours: -6},
+		},
+		{
+			name:     "Across DST",
+			start:    time.Date(2024, 3, 9, 12, 0, 0, 0, newYork),
+			end:      time.Date(2024, 3, 11, 12, 0, 0, 0, newYork),
+			expected: dateutils.Period{Days: 2},
+		},
+		{
+			name:     "Location",
+			start:    time.Date(2024, 3, 9, 17, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 3, 11, 16, 0, 0, 0, time.UTC),
+			options:  dateutils.DiffOptions{Location: newYork},
+			expected: dateutils.Period{Days: 2},
+		},
+		{
+			name:     "Weeks",
+			start:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC),
+			options:  dateutils.DiffOptions{Weeks: true},
+			expected: dateutils.Period{Weeks: 2, Days: 3},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			diff := dateutils.Diff(testCase.start, testCase.end, testCase.options)
+			assert.Equal(t, testCase.expected, diff)
+
+			base := testCase.start
+			if testCase.options.Location != nil {
+				base = base.In(testCase.options.Location)
+			}
+			assert.True(t, diff.AddTo(base).Equal(testCase.end))
+		})
+	}
+}
+
+func TestDiffRoundTrip(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	start := time.Date(2023, 10, 31, 1, 30, 0, 0, newYork)
+	for hours := -24 * 400; hours <= 24*400; hours += 7 {
+		end := start.Add(time.Duration(hours) * time.Hour)
+		assert.True(t, dateutils.Diff(start, end).AddTo(start).Equal(end), "end %s", end)
+	}
+}
+
+func TestMonthsBetween(t *testing.T) {
+	assert.Equal(t, 1, dateutils.MonthsBetween(
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0, dateutils.MonthsBetween(
+		time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), time.Date(2024, 2, 15, 11, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 14, dateutils.MonthsBetween(
+		time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, -2, dateutils.MonthsBetween(
+		time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestWeeksBetween(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, newYork)
+	assert.Equal(t, 1, dateutils.WeeksBetween(start, time.Date(2024, 3, 11, 0, 0, 0, 0, newYork)))
+	assert.Equal(t, 0, dateutils.WeeksBetween(start, time.Date(2024, 3, 10, 23, 0, 0, 0, newYork)))
+	assert.Equal(t, -2, dateutils.WeeksBetween(start, time.Date(2024, 2, 18, 0, 0, 0, 0, newYork)))
+}
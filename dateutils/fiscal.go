@@ -0,0 +1,242 @@
+package dateutils
+
+import "time"
+
+// FiscalPattern - how a fiscal year is divided into periods.
+type FiscalPattern int
+
+const (
+	// CalendarMonths uses calendar months as periods; the fiscal year starts on the first day of the start month.
+	CalendarMonths FiscalPattern = iota
+	// Retail445 uses 52- or 53-week years whose quarters have periods of 4, 4 and 5 weeks.
+	Retail445
+	// Retail454 uses 52- or 53-week years whose quarters have periods of 4, 5 and 4 weeks.
+	Retail454
+	// Retail544 uses 52- or 53-week years whose quarters have periods of 5, 4 and 4 weeks.
+	Retail544
+)
+
+var retailPeriodWeeks = map[FiscalPattern][3]int{
+	Retail445: {4, 4, 5},
+	Retail454: {4, 5, 4},
+	Retail544: {5, 4, 4},
+}
+
+// FiscalYearEnd - how retail fiscal years choose their last day.
+type FiscalYearEnd int
+
+const (
+	// YearEndLastWeekday ends the year on the last end-of-week day of the month before the start month.
+	YearEndLastWeekday FiscalYearEnd = iota
+	// YearEndNearestWeekday ends the year on the end-of-week day nearest the end of the month before the start month,
+	// which may fall in the first days of the start month.
+	YearEndNearestWeekday
+)
+
+// FiscalOptions - fiscal calendar options.
+type FiscalOptions struct {
+	StartMonth       time.Month
+	Pattern          FiscalPattern
+	WeekStart        time.Weekday
+	YearEnd          FiscalYearEnd
+	LabelByStartYear bool
+}
+
+func parseFiscalOptions(opts ...FiscalOptions) FiscalOptions {
+	options := FiscalOptions{
+		StartMonth: time.January,
+		Pattern:    CalendarMonths,
+		WeekStart:  time.Sunday,
+		YearEnd:    YearEndLastWeekday,
+	}
+
+	for _, opt := range opts {
+		if opt.StartMonth != 0 {
+			options.StartMonth = opt.StartMonth
+		}
+		if opt.Pattern != CalendarMonths {
+			options.Pattern = opt.Pattern
+		}
+		if opt.WeekStart != time.Sunday {
+			options.WeekStart = opt.WeekStart
+		}
+		if opt.YearEnd != YearEndLastWeekday {
+			options.YearEnd = opt.YearEnd
+		}
+		if opt.LabelByStartYear {
+			options.LabelByStartYear = true
+		}
+	}
+
+	return options
+}
+
+// FiscalDate is the position of a date in a fiscal calendar.
+type FiscalDate struct {
+	Year    int
+	Quarter int
+	Period  int
+	Week    int
+}
+
+// FiscalCalendar maps dates to fiscal years, quarters, periods and weeks. Each fiscal year has four quarters of
+// three periods. Weeks are counted from the first day of the fiscal year, so the last week of a calendar-month
+// year may be shorter than seven days.
+type FiscalCalendar struct {
+	options FiscalOptions
+}
+
+// NewFiscalCalendar creates a fiscal calendar. Fiscal years are labeled by the calendar year in which they end,
+// so with a start month of October, fiscal year 2024 runs from October 2023 to September 2024.
+// Retail patterns have 52-week years, with a 53rd week added to the last period when needed to keep the year end on
+// the same day of the week.
+// NewFiscalCalendar also accepts an options object with the following properties:
+//
+//	FiscalOptions.StartMonth: the month the fiscal year starts in, defaults to January.
+//	FiscalOptions.Pattern: CalendarMonths, Retail445, Retail454 or Retail544, defaults to CalendarMonths.
+//	FiscalOptions.WeekStart: the first day of retail weeks, defaults to Sunday, so years end on a Saturday.
+//	FiscalOptions.YearEnd: YearEndLastWeekday or YearEndNearestWeekday for retail patterns, defaults to YearEndLastWeekday.
+//	FiscalOptions.LabelByStartYear: label fiscal years by the calendar year in which they start, defaults to false.
+func NewFiscalCalendar(opts ...FiscalOptions) *FiscalCalendar {
+	return &FiscalCalendar{options: parseFiscalOptions(opts...)}
+}
+
+// FiscalDate returns the fiscal year, quarter, period and week of t's date.
+func (c *FiscalCalendar) FiscalDate(t time.Time) FiscalDate {
+	endYear, starts, period := c.locate(DateOf(t))
+	return FiscalDate{
+		Year:    c.label(endYear),
+		Quarter: (period-1)/3 + 1,
+		Period:  period,
+		Week:    DateOf(t).DaysSince(starts[0])/7 + 1,
+	}
+}
+
+// Year returns the fiscal year of t and its boundaries, as a half-open interval in t's location.
+func (c *FiscalCalendar) Year(t time.Time) (int, Interval) {
+	endYear, starts, _ := c.locate(DateOf(t))
+	return c.label(endYear), dateInterval(starts[0], starts[12], t.Location())
+}
+
+// Quarter returns the fiscal quarter of t, from 1 to 4, and its boundaries.
+func (c *FiscalCalendar) Quarter(t time.Time) (int, Interval) {
+	_, starts, period := c.locate(DateOf(t))
+	quarter := (period-1)/3 + 1
+	return quarter, dateInterval(starts[3*quarter-3], starts[3*quarter], t.Location())
+}
+
+// Period returns the fiscal period of t, from 1 to 12, and its boundaries.
+func (c *FiscalCalendar) Period(t time.Time) (int, Interval) {
+	_, starts, period := c.locate(DateOf(t))
+	return period, dateInterval(starts[period-1], starts[period], t.Location())
+}
+
+// Week returns the fiscal week of t, from 1 to 53, and its boundaries.
+func (c *FiscalCalendar) Week(t time.Time) (int, Interval) {
+	date := DateOf(t)
+	_, starts, _ := c.locate(date)
+
+	week := date.DaysSince(starts[0])/7 + 1
+	start := starts[0].AddDays(7 * (week - 1))
+	end := start.AddDays(7)
+	if end.After(starts[12]) {
+		end = starts[12]
+	}
+	return week, dateInterval(start, end, t.Location())
+}
+
+// YearBounds returns the boundaries of the given fiscal year in loc.
+func (c *FiscalCalendar) YearBounds(fiscalYear int, loc *time.Location) Interval {
+	endYear := fiscalYear
+	if c.labelsByStartYear() {
+		endYear++
+	}
+	starts := c.periodStarts(endYear)
+	return dateInterval(starts[0], starts[12], loc)
+}
+
+// WeeksInYear returns the number of weeks in the given fiscal year: 52 or 53 for retail patterns. For calendar-month
+// patterns, a partial last week counts as a week.
+func (c *FiscalCalendar) WeeksInYear(fiscalYear int) int {
+	bounds := c.YearBounds(fiscalYear, time.UTC)
+	days := DateOf(bounds.End).DaysSince(DateOf(bounds.Start))
+	return (days + 6) / 7
+}
+
+// locate returns the calendar year in which the fiscal year of date ends, the start dates of its periods followed by
+// the start of the next year, and the period of date.
+func (c *FiscalCalendar) locate(date Date) (int, []Date, int) {
+	endYear := date.Year
+	switch {
+	case !date.After(c.yearEnd(endYear - 1)):
+		endYear--
+	case date.After(c.yearEnd(endYear)):
+		endYear++
+	}
+
+	starts := c.periodStarts(endYear)
+	period := 1
+	for period < 12 && !date.Before(starts[period]) {
+		period++
+	}
+	return endYear, starts, period
+}
+
+// periodStarts returns the first day of each period of the fiscal year ending in endYear, followed by the first day
+// of the next fiscal year.
+func (c *FiscalCalendar) periodStarts(endYear int) []Date {
+	starts := make([]Date, 13)
+
+	weeks, retail := retailPeriodWeeks[c.options.Pattern]
+	if !retail {
+		first := NewDate(endYear-1, c.options.StartMonth, 1)
+		if c.options.StartMonth == time.January {
+			first = NewDate(endYear, time.January, 1)
+		}
+		for index := range starts {
+			starts[index] = first.AddMonths(index)
+		}
+		return starts
+	}
+
+	starts[0] = c.yearEnd(endYear - 1).AddDays(1)
+	for index := 1; index < 12; index++ {
+		starts[index] = starts[index-1].AddDays(7 * weeks[(index-1)%3])
+	}
+	starts[12] = c.yearEnd(endYear).AddDays(1)
+	return starts
+}
+
+// yearEnd returns the last day of the fiscal year ending in endYear.
+func (c *FiscalCalendar) yearEnd(endYear int) Date {
+	endMonth := c.options.StartMonth - 1
+	if endMonth == 0 {
+		endMonth = time.December
+	}
+	lastOfMonth := NewDate(endYear, endMonth+1, 0)
+	if _, retail := retailPeriodWeeks[c.options.Pattern]; !retail {
+		return lastOfMonth
+	}
+
+	endWeekday := (c.options.WeekStart + 6) % 7
+	back := daysSinceWeekStart(lastOfMonth.Weekday(), endWeekday)
+	if c.options.YearEnd == YearEndNearestWeekday && back > 3 {
+		return lastOfMonth.AddDays(7 - back)
+	}
+	return lastOfMonth.AddDays(-back)
+}
+
+func (c *FiscalCalendar) labelsByStartYear() bool {
+	return c.options.LabelByStartYear && c.options.StartMonth != time.January
+}
+
+func (c *FiscalCalendar) label(endYear int) int {
+	if c.labelsByStartYear() {
+		return endYear - 1
+	}
+	return endYear
+}
+
+func dateInterval(start, end Date, loc *time.Location) Interval {
+	return NewInterval(start.In(loc), end.In(loc))
+}
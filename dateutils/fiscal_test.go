@@ -0,0 +1,145 @@
+package dateutils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func fiscalDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func TestFiscalCalendarMonths(t *testing.T) {
+	calendar := dateutils.NewFiscalCalendar(dateutils.FiscalOptions{StartMonth: time.October})
+
+	for _, testCase := range []struct {
+		name     string
+		date     time.Time
+		expected dateutils.FiscalDate
+	}{
+		{"First day", fiscalDay(2023, time.October, 1), dateutils.FiscalDate{Year: 2024, Quarter: 1, Period: 1, Week: 1}},
+		{"Calendar new year", fiscalDay(2024, time.January, 1), dateutils.FiscalDate{Year: 2024, Quarter: 2, Period: 4, Week: 14}},
+		{"Last day", fiscalDay(2024, time.September, 30), dateutils.FiscalDate{Year: 2024, Quarter: 4, Period: 12, Week: 53}},
+		{"Next year", fiscalDay(2024, time.October, 1), dateutils.FiscalDate{Year: 2025, Quarter: 1, Period: 1, Week: 1}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, calendar.FiscalDate(testCase.date))
+		})
+	}
+
+	year, bounds := calendar.Year(fiscalDay(2024, time.March, 15))
+	assert.Equal(t, 2024, year)
+	assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), bounds.End)
+
+	quarter, bounds := calendar.Quarter(fiscalDay(2024, time.March, 15))
+	assert.Equal(t, 2, quarter)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), bounds.End)
+
+	period, bounds := calendar.Period(fiscalDay(2024, time.February, 29))
+	assert.Equal(t, 5, period)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), bounds.End)
+
+	week, bounds := calendar.Week(fiscalDay(2024, time.September, 30))
+	assert.Equal(t, 53, week)
+	assert.Equal(t, time.Date(2024, 9, 29, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), bounds.End)
+
+	assert.Equal(t, 53, calendar.WeeksInYear(2024))
+}
+
+func TestFiscalCalendarDefault(t *testing.T) {
+	calendar := dateutils.NewFiscalCalendar()
+
+	assert.Equal(t, dateutils.FiscalDate{Year: 2024, Quarter: 3, Period: 8, Week: 33},
+		calendar.FiscalDate(fiscalDay(2024, time.August, 15)))
+	assert.Equal(t, dateutils.NewInterval(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)), calendar.YearBounds(2024, time.UTC))
+}
+
+func TestFiscalCalendarLabelByStartYear(t *testing.T) {
+	calendar := dateutils.NewFiscalCalendar(dateutils.FiscalOptions{StartMonth: time.April, LabelByStartYear: true})
+
+	year, bounds := calendar.Year(fiscalDay(2025, time.March, 31))
+	assert.Equal(t, 2024, year)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, bounds, calendar.YearBounds(2024, time.UTC))
+}
+
+func TestFiscalCalendarRetail(t *testing.T) {
+	// The NRF retail calendar: 4-5-4 weeks, ending on the Saturday nearest the end of January.
+	calendar := dateutils.NewFiscalCalendar(dateutils.FiscalOptions{
+		StartMonth:       time.February,
+		Pattern:          dateutils.Retail454,
+		YearEnd:          dateutils.YearEndNearestWeekday,
+		LabelByStartYear: true,
+	})
+
+	assert.Equal(t, dateutils.NewInterval(time.Date(2023, 1, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)), calendar.YearBounds(2023, time.UTC))
+	assert.Equal(t, 53, calendar.WeeksInYear(2023))
+	assert.Equal(t, 52, calendar.WeeksInYear(2024))
+
+	for _, testCase := range []struct {
+		name     string
+		date     time.Time
+		expected dateutils.FiscalDate
+	}{
+		{"First day", fiscalDay(2023, time.January, 29), dateutils.FiscalDate{Year: 2023, Quarter: 1, Period: 1, Week: 1}},
+		{"Five-week period", fiscalDay(2023, time.March, 1), dateutils.FiscalDate{Year: 2023, Quarter: 1, Period: 2, Week: 5}},
+		{"Second quarter", fiscalDay(2023, time.April, 30), dateutils.FiscalDate{Year: 2023, Quarter: 2, Period: 4, Week: 14}},
+		{"53rd week", fiscalDay(2024, time.February, 3), dateutils.FiscalDate{Year: 2023, Quarter: 4, Period: 12, Week: 53}},
+		{"Next year", fiscalDay(2024, time.February, 4), dateutils.FiscalDate{Year: 2024, Quarter: 1, Period: 1, Week: 1}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, calendar.FiscalDate(testCase.date))
+		})
+	}
+
+	period, bounds := calendar.Period(fiscalDay(2024, time.January, 15))
+	assert.Equal(t, 12, period)
+	assert.Equal(t, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC), bounds.End)
+
+	quarter, bounds := calendar.Quarter(fiscalDay(2023, time.March, 1))
+	assert.Equal(t, 1, quarter)
+	assert.Equal(t, time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC), bounds.End)
+
+	week, bounds := calendar.Week(fiscalDay(2023, time.March, 1))
+	assert.Equal(t, 5, week)
+	assert.Equal(t, time.Date(2023, 2, 26, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC), bounds.End)
+}
+
+func TestFiscalCalendarRetailLastWeekday(t *testing.T) {
+	calendar := dateutils.NewFiscalCalendar(dateutils.FiscalOptions{Pattern: dateutils.Retail445, WeekStart: time.Monday})
+
+	// 2024 ends on the last Sunday of December 2024, the 29th; 2023 ended on Sunday, December 31st.
+	year, bounds := calendar.Year(fiscalDay(2024, time.December, 30))
+	assert.Equal(t, 2025, year)
+	assert.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), bounds.Start)
+
+	assert.Equal(t, dateutils.NewInterval(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)), calendar.YearBounds(2024, time.UTC))
+
+	period, bounds := calendar.Period(fiscalDay(2024, time.March, 20))
+	assert.Equal(t, 3, period)
+	assert.Equal(t, time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC), bounds.Start)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), bounds.End)
+}
+
+func TestFiscalCalendarLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	calendar := dateutils.NewFiscalCalendar(dateutils.FiscalOptions{StartMonth: time.July})
+	year, bounds := calendar.Year(time.Date(2024, 6, 30, 23, 0, 0, 0, newYork))
+	assert.Equal(t, 2024, year)
+	assert.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, newYork), bounds.Start)
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, newYork), bounds.End)
+}
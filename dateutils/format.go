@@ -0,0 +1,1096 @@
+package dateutils
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownDirective is returned when a format contains a strftime directive or token that is not supported.
+	ErrUnknownDirective = errors.New("dateutils: unknown format directive")
+	// ErrNoGoLayout is returned when a format has no equivalent Go layout, because it uses a directive the time
+	// package lacks, such as an ISO week or an ordinal day, or literal text that Go would read as a layout element.
+	ErrNoGoLayout = errors.New("dateutils: format has no Go layout equivalent")
+)
+
+type formatKind int
+
+const (
+	fieldYear formatKind = iota
+	fieldYear2
+	fieldCentury
+	fieldQuarter
+	fieldMonth
+	fieldMonthName
+	fieldMonthShort
+	fieldDay
+	fieldDayOrdinal
+	fieldYearDay
+	fieldWeekday
+	fieldISOWeekday
+	fieldWeekdayName
+	fieldWeekdayShort
+	fieldWeekdayMin
+	fieldISOYear
+	fieldISOYear2
+	fieldISOWeek
+	fieldWeekOfYearSunday
+	fieldWeekOfYearMonday
+	fieldHour
+	fieldHour12
+	fieldMinute
+	fieldSecond
+	fieldFraction
+	fieldAMPM
+	fieldAMPMLower
+	fieldOffset
+	fieldOffsetColon
+	fieldOffsetZulu
+	fieldZoneName
+	fieldUnix
+	fieldUnixMilli
+	fieldCount
+)
+
+// formatField is a directive or token. Numbers are padded with pad to width digits, unless pad is zero;
+// fractions have exactly width digits.
+type formatField struct {
+	kind  formatKind
+	width int
+	pad   byte
+}
+
+type formatElement struct {
+	literal string
+	field   formatField
+	isField bool
+}
+
+// compiledFormat is a strftime or token format split into literals and fields. layout is the equivalent Go
+// layout, or empty if there is none; err records an unknown directive.
+type compiledFormat struct {
+	elements []formatElement
+	layout   string
+	err      error
+}
+
+var strftimeDirectives = map[byte]formatField{
+	'a': {kind: fieldWeekdayShort},
+	'A': {kind: fieldWeekdayName},
+	'b': {kind: fieldMonthShort},
+	'h': {kind: fieldMonthShort},
+	'B': {kind: fieldMonthName},
+	'C': {kind: fieldCentury, width: 2, pad: '0'},
+	'd': {kind: fieldDay, width: 2, pad: '0'},
+	'e': {kind: fieldDay, width: 2, pad: ' '},
+	'f': {kind: fieldFraction, width: 6},
+	'g': {kind: fieldISOYear2, width: 2, pad: '0'},
+	'G': {kind: fieldISOYear, width: 4, pad: '0'},
+	'H': {kind: fieldHour, width: 2, pad: '0'},
+	'I': {kind: fieldHour12, width: 2, pad: '0'},
+	'j': {kind: fieldYearDay, width: 3, pad: '0'},
+	'k': {kind: fieldHour, width: 2, pad: ' '},
+	'l': {kind: fieldHour12, width: 2, pad: ' '},
+	'm': {kind: fieldMonth, width: 2, pad: '0'},
+	'M': {kind: fieldMinute, width: 2, pad: '0'},
+	'N': {kind: fieldFraction, width: 9},
+	'p': {kind: fieldAMPM},
+	'P': {kind: fieldAMPMLower},
+	'q': {kind: fieldQuarter},
+	's': {kind: fieldUnix},
+	'S': {kind: fieldSecond, width: 2, pad: '0'},
+	'u': {kind: fieldISOWeekday},
+	'U': {kind: fieldWeekOfYearSunday, width: 2, pad: '0'},
+	'V': {kind: fieldISOWeek, width: 2, pad: '0'},
+	'w': {kind: fieldWeekday},
+	'W': {kind: fieldWeekOfYearMonday, width: 2, pad: '0'},
+	'y': {kind: fieldYear2, width: 2, pad: '0'},
+	'Y': {kind: fieldYear, width: 4, pad: '0'},
+	'z': {kind: fieldOffset},
+	'Z': {kind: fieldZoneName},
+}
+
+var strftimeExpansions = map[byte]string{
+	'c': "%a %b %e %H:%M:%S %Y",
+	'D': "%m/%d/%y",
+	'F': "%Y-%m-%d",
+	'r': "%I:%M:%S %p",
+	'R': "%H:%M",
+	'T': "%H:%M:%S",
+	'x': "%m/%d/%y",
+	'X': "%H:%M:%S",
+}
+
+var strftimeLiterals = map[byte]string{'%': "%", 'n': "\n", 't': "\t"}
+
+type formatToken struct {
+	token string
+	field formatField
+}
+
+// formatSyntax selects how a format is read.
+type formatSyntax int
+
+const (
+	strftimeSyntax formatSyntax = iota
+	momentSyntax
+	dateFnsSyntax
+)
+
+// momentTokens and dateFnsTokens hold the moment.js and date-fns tokens, longest first.
+var (
+	momentTokens  = buildMomentTokens()
+	dateFnsTokens = buildDateFnsTokens()
+)
+
+func buildMomentTokens() []formatToken {
+	tokens := []formatToken{
+		{"YYYY", formatField{kind: fieldYear, width: 4, pad: '0'}},
+		{"YY", formatField{kind: fieldYear2, width: 2, pad: '0'}},
+		{"Q", formatField{kind: fieldQuarter}},
+		{"MMMM", formatField{kind: fieldMonthName}},
+		{"MMM", formatField{kind: fieldMonthShort}},
+		{"MM", formatField{kind: fieldMonth, width: 2, pad: '0'}},
+		{"M", formatField{kind: fieldMonth}},
+		{"DDDD", formatField{kind: fieldYearDay, width: 3, pad: '0'}},
+		{"DDD", formatField{kind: fieldYearDay}},
+		{"Do", formatField{kind: fieldDayOrdinal}},
+		{"DD", formatField{kind: fieldDay, width: 2, pad: '0'}},
+		{"D", formatField{kind: fieldDay}},
+		{"dddd", formatField{kind: fieldWeekdayName}},
+		{"ddd", formatField{kind: fieldWeekdayShort}},
+		{"dd", formatField{kind: fieldWeekdayMin}},
+		{"d", formatField{kind: fieldWeekday}},
+		{"E", formatField{kind: fieldISOWeekday}},
+		{"GGGG", formatField{kind: fieldISOYear, width: 4, pad: '0'}},
+		{"GG", formatField{kind: fieldISOYear2, width: 2, pad: '0'}},
+		{"WW", formatField{kind: fieldISOWeek, width: 2, pad: '0'}},
+		{"W", formatField{kind: fieldISOWeek}},
+		{"HH", formatField{kind: fieldHour, width: 2, pad: '0'}},
+		{"H", formatField{kind: fieldHour}},
+		{"hh", formatField{kind: fieldHour12, width: 2, pad: '0'}},
+		{"h", formatField{kind: fieldHour12}},
+		{"mm", formatField{kind: fieldMinute, width: 2, pad: '0'}},
+		{"m", formatField{kind: fieldMinute}},
+		{"ss", formatField{kind: fieldSecond, width: 2, pad: '0'}},
+		{"s", formatField{kind: fieldSecond}},
+		{"A", formatField{kind: fieldAMPM}},
+		{"a", formatField{kind: fieldAMPMLower}},
+		{"ZZ", formatField{kind: fieldOffset}},
+		{"Z", formatField{kind: fieldOffsetColon}},
+		{"zz", formatField{kind: fieldZoneName}},
+		{"z", formatField{kind: fieldZoneName}},
+		{"X", formatField{kind: fieldUnix}},
+		{"x", formatField{kind: fieldUnixMilli}},
+	}
+	return sortTokens(tokens)
+}
+
+func buildDateFnsTokens() []formatToken {
+	tokens := []formatToken{
+		{"yyyy", formatField{kind: fieldYear, width: 4, pad: '0'}},
+		{"yy", formatField{kind: fieldYear2, width: 2, pad: '0'}},
+		{"y", formatField{kind: fieldYear}},
+		{"Q", formatField{kind: fieldQuarter}},
+		{"MMMM", formatField{kind: fieldMonthName}},
+		{"MMM", formatField{kind: fieldMonthShort}},
+		{"MM", formatField{kind: fieldMonth, width: 2, pad: '0'}},
+		{"M", formatField{kind: fieldMonth}},
+		{"do", formatField{kind: fieldDayOrdinal}},
+		{"dd", formatField{kind: fieldDay, width: 2, pad: '0'}},
+		{"d", formatField{kind: fieldDay}},
+		{"DDD", formatField{kind: fieldYearDay, width: 3, pad: '0'}},
+		{"D", formatField{kind: fieldYearDay}},
+		{"EEEEEE", formatField{kind: fieldWeekdayMin}},
+		{"EEEE", formatField{kind: fieldWeekdayName}},
+		{"EEE", formatField{kind: fieldWeekdayShort}},
+		{"EE", formatField{kind: fieldWeekdayShort}},
+		{"E", formatField{kind: fieldWeekdayShort}},
+		{"i", formatField{kind: fieldISOWeekday}},
+		{"RRRR", formatField{kind: fieldISOYear, width: 4, pad: '0'}},
+		{"RR", formatField{kind: fieldISOYear2, width: 2, pad: '0'}},
+		{"II", formatField{kind: fieldISOWeek, width: 2, pad: '0'}},
+		{"I", formatField{kind: fieldISOWeek}},
+		{"HH", formatField{kind: fieldHour, width: 2, pad: '0'}},
+		{"H", formatField{kind: fieldHour}},
+		{"hh", formatField{kind: fieldHour12, width: 2, pad: '0'}},
+		{"h", formatField{kind: fieldHour12}},
+		{"mm", formatField{kind: fieldMinute, width: 2, pad: '0'}},
+		{"m", formatField{kind: fieldMinute}},
+		{"ss", formatField{kind: fieldSecond, width: 2, pad: '0'}},
+		{"s", formatField{kind: fieldSecond}},
+		{"aaa", formatField{kind: fieldAMPMLower}},
+		{"aa", formatField{kind: fieldAMPM}},
+		{"a", formatField{kind: fieldAMPM}},
+		{"XXX", formatField{kind: fieldOffsetZulu}},
+		{"xxx", formatField{kind: fieldOffsetColon}},
+		{"xx", formatField{kind: fieldOffset}},
+		{"zzz", formatField{kind: fieldZoneName}},
+		{"zz", formatField{kind: fieldZoneName}},
+		{"z", formatField{kind: fieldZoneName}},
+		{"t", formatField{kind: fieldUnix}},
+		{"T", formatField{kind: fieldUnixMilli}},
+	}
+	return sortTokens(tokens)
+}
+
+// sortTokens adds the fraction tokens, from "S" to "SSSSSSSSS", and sorts the tokens longest first.
+func sortTokens(tokens []formatToken) []formatToken {
+	for digits := 1; digits <= 9; digits++ {
+		tokens = append(tokens, formatToken{strings.Repeat("S", digits), formatField{kind: fieldFraction, width: digits}})
+	}
+
+	slices.SortStableFunc(tokens, func(a, b formatToken) int { return len(b.token) - len(a.token) })
+	return tokens
+}
+
+// FormatStrftime formats t using strftime directives, such as "%Y-%m-%d %H:%M:%S".
+// Besides the C and POSIX directives, it supports %f (microseconds), %N (nanoseconds), %q (quarter), %P (lowercase
+// am/pm), %:z (offset with a colon) and the "-" flag for unpadded numbers, as in "%-d". Directives that Go layouts
+// lack, such as %-j (unpadded day of the year), %U, %W and %V (week numbers) and %G (ISO week year), are handled
+// directly.
+// Names are in English and the locale-dependent %c, %x and %X use the C locale. Unknown directives are written as is.
+// Formats are translated once and cached.
+func FormatStrftime(t time.Time, format string) string {
+	return compiledFormatFor(strftimeSyntax, format).format(t)
+}
+
+// ParseStrftime parses value using strftime directives, as in FormatStrftime. Values without a time zone are read
+// in DateParseOptions.Location, which defaults to UTC; the other options are ignored. Dates may be given by year,
+// month and day, by day of the year (%j), by ISO week year, week and weekday (%G-W%V-%u), or by week of the year and
+// weekday (%U or %W with %a or %w). Two-digit years are placed in 1969-2068. Missing fields default to year 0,
+// January 1st and midnight, like time.Parse.
+func ParseStrftime(value, format string, opts ...DateParseOptions) (time.Time, error) {
+	return compiledFormatFor(strftimeSyntax, format).parse(value, parseDateParseOptions(opts...).Location)
+}
+
+// StrftimeLayout translates strftime directives into a Go layout, so that "%Y-%m-%d %H:%M" becomes
+// "2006-01-02 15:04". It returns ErrNoGoLayout if the format uses directives that Go layouts lack, such as %V,
+// or literal text that Go would read as a layout element, and ErrUnknownDirective for unsupported directives.
+func StrftimeLayout(format string) (string, error) {
+	return compiledFormatFor(strftimeSyntax, format).goLayout(format)
+}
+
+// FormatTokens formats t using moment.js tokens, such as "YYYY-MM-DD HH:mm:ss". Text in square brackets is written
+// as is: "[Week] W"; an unclosed bracket makes the rest of the format literal. Tokens that Go layouts lack, such as
+// "Do" (ordinal day, "1st"), "DDD" (unpadded day of the year), "W" (ISO week), "GGGG" (ISO week year), "E" (ISO
+// weekday) and "X" (Unix seconds), are handled directly. Names are in English. Formats are translated once and
+// cached.
+func FormatTokens(t time.Time, format string) string {
+	return compiledFormatFor(momentSyntax, format).format(t)
+}
+
+// ParseTokens parses value using moment.js tokens, as in FormatTokens. See ParseStrftime for the handling of
+// locations, dates and missing fields.
+func ParseTokens(value, format string, opts ...DateParseOptions) (time.Time, error) {
+	return compiledFormatFor(momentSyntax, format).parse(value, parseDateParseOptions(opts...).Location)
+}
+
+// TokensLayout translates moment.js tokens into a Go layout, so that "YYYY-MM-DD HH:mm" becomes
+// "2006-01-02 15:04". It returns ErrNoGoLayout if the format uses tokens that Go layouts lack, such as "Do", or
+// literal text that Go would read as a layout element.
+func TokensLayout(format string) (string, error) {
+	return compiledFormatFor(momentSyntax, format).goLayout(format)
+}
+
+// FormatDateFns formats t using date-fns (Unicode) tokens, such as "yyyy-MM-dd HH:mm:ss". Unlike moment.js, "d" is
+// the day of the month, "D" the day of the year, "EEEE" the weekday, "i" the ISO weekday, "RRRR" the ISO week year
+// and "I" the ISO week. Text in single quotes is written as is: "'Week' I"; two single quotes write one, and an
+// unclosed quote makes the rest of the format literal. Names are in English. Formats are translated once and cached.
+func FormatDateFns(t time.Time, format string) string {
+	return compiledFormatFor(dateFnsSyntax, format).format(t)
+}
+
+// ParseDateFns parses value using date-fns tokens, as in FormatDateFns. See ParseStrftime for the handling of
+// locations, dates and missing fields.
+func ParseDateFns(value, format string, opts ...DateParseOptions) (time.Time, error) {
+	return compiledFormatFor(dateFnsSyntax, format).parse(value, parseDateParseOptions(opts...).Location)
+}
+
+// DateFnsLayout translates date-fns tokens into a Go layout, so that "yyyy-MM-dd HH:mm" becomes "2006-01-02 15:04".
+// It returns ErrNoGoLayout if the format uses tokens that Go layouts lack, such as "do", or literal text that Go
+// would read as a layout element.
+func DateFnsLayout(format string) (string, error) {
+	return compiledFormatFor(dateFnsSyntax, format).goLayout(format)
+}
+
+// formatCacheSize bounds the number of cached formats; the cache is cleared when it is full.
+const formatCacheSize = 512
+
+type formatCacheKey struct {
+	syntax formatSyntax
+	format string
+}
+
+var formatCache = struct {
+	mu      sync.Mutex
+	entries map[formatCacheKey]*compiledFormat
+}{entries: make(map[formatCacheKey]*compiledFormat)}
+
+func compiledFormatFor(syntax formatSyntax, format string) *compiledFormat {
+	key := formatCacheKey{syntax: syntax, format: format}
+
+	formatCache.mu.Lock()
+	defer formatCache.mu.Unlock()
+
+	if cached, ok := formatCache.entries[key]; ok {
+		return cached
+	}
+	if len(formatCache.entries) >= formatCacheSize {
+		clear(formatCache.entries)
+	}
+
+	compiled := &compiledFormat{}
+	if syntax != strftimeSyntax {
+		compiled.elements = compileTokens(format, syntax)
+	} else {
+		compiled.elements, compiled.err = compileStrftime(format)
+	}
+	compiled.layout = compiled.translate()
+	formatCache.entries[key] = compiled
+	return compiled
+}
+
+func compileStrftime(format string) ([]formatElement, error) {
+	var elements []formatElement
+	var unknown error
+
+	for index := 0; index < len(format); index++ {
+		if format[index] != '%' || index == len(format)-1 {
+			elements = appendLiteral(elements, format[index:index+1])
+			continue
+		}
+
+		start := index
+		index++
+		flag := byte(0)
+		if format[index] == '-' || format[index] == ':' {
+			flag = format[index]
+			if index == len(format)-1 {
+				elements = appendLiteral(elements, format[start:])
+				break
+			}
+			index++
+		}
+
+		directive := format[index]
+		field, isField := strftimeDirectives[directive]
+		expansion, isExpansion := strftimeExpansions[directive]
+		literal, isLiteral := strftimeLiterals[directive]
+
+		switch {
+		case flag == ':' && directive == 'z':
+			elements = append(elements, formatElement{field: formatField{kind: fieldOffsetColon}, isField: true})
+		case flag == 0 && isExpansion:
+			expanded, _ := compileStrftime(expansion)
+			for _, element := range expanded {
+				if element.isField {
+					elements = append(elements, element)
+				} else {
+					elements = appendLiteral(elements, element.literal)
+				}
+			}
+		case flag == 0 && isLiteral:
+			elements = appendLiteral(elements, literal)
+		case isField && (flag == 0 || field.width > 1 && field.kind != fieldFraction):
+			if flag == '-' {
+				field.pad = 0
+			}
+			elements = append(elements, formatElement{field: field, isField: true})
+		default:
+			elements = appendLiteral(elements, format[start:index+1])
+			if unknown == nil {
+				unknown = fmt.Errorf("%w: %q in %q", ErrUnknownDirective, format[start:index+1], format)
+			}
+		}
+	}
+	return elements, unknown
+}
+
+func compileTokens(format string, syntax formatSyntax) []formatElement {
+	var elements []formatElement
+	tokens := momentTokens
+	if syntax == dateFnsSyntax {
+		tokens = dateFnsTokens
+	}
+
+	for index := 0; index < len(format); {
+		if literal, length := escapedLiteral(format[index:], syntax); length > 0 {
+			elements = appendLiteral(elements, literal)
+			index += length
+			continue
+		}
+
+		matched := false
+		for _, token := range tokens {
+			if strings.HasPrefix(format[index:], token.token) {
+				elements = append(elements, formatElement{field: token.field, isField: true})
+				index += len(token.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			elements = appendLiteral(elements, format[index:index+1])
+			index++
+		}
+	}
+	return elements
+}
+
+// escapedLiteral returns the literal text at the start of format and its length in format, or a zero length if
+// format does not start with escaped text: "[text]" for moment.js, "'text'" for date-fns, where two single quotes
+// are one quote. Escaped text that is not closed runs to the end of the format.
+func escapedLiteral(format string, syntax formatSyntax) (string, int) {
+	if syntax == momentSyntax && strings.HasPrefix(format, "[") {
+		if end := strings.IndexByte(format, ']'); end > 0 {
+			return format[1:end], end + 1
+		}
+		return format[1:], len(format)
+	}
+	if syntax != dateFnsSyntax || !strings.HasPrefix(format, "'") {
+		return "", 0
+	}
+	if strings.HasPrefix(format, "''") {
+		return "'", 2
+	}
+
+	var literal strings.Builder
+	for index := 1; index < len(format); index++ {
+		switch {
+		case format[index] != '\'':
+			literal.WriteByte(format[index])
+		case strings.HasPrefix(format[index:], "''"):
+			literal.WriteByte('\'')
+			index++
+		default:
+			return literal.String(), index + 1
+		}
+	}
+	return literal.String(), len(format)
+}
+
+func appendLiteral(elements []formatElement, literal string) []formatElement {
+	if count := len(elements); count > 0 && !elements[count-1].isField {
+		elements[count-1].literal += literal
+		return elements
+	}
+	return append(elements, formatElement{literal: literal})
+}
+
+// goLayoutProbes are formatted with both the Go layout and the elements, to reject translations that Go would
+// split into layout elements differently.
+var goLayoutProbes = []time.Time{
+	time.Date(2009, time.November, 17, 20, 34, 58, 651387237, time.FixedZone("IST", 19800)),
+	time.Date(2023, time.February, 3, 4, 5, 6, 7000000, time.FixedZone("PST", -28800)),
+}
+
+// translate returns the Go layout equivalent to the elements, or an empty string if there is none.
+func (f *compiledFormat) translate() string {
+	if f.err != nil {
+		return ""
+	}
+
+	var layout strings.Builder
+	for _, element := range f.elements {
+		if !element.isField {
+			if !isLayoutSafe(element.literal) {
+				return ""
+			}
+			layout.WriteString(element.literal)
+			continue
+		}
+
+		if element.field.kind == fieldFraction {
+			// Go fractions include the separator that precedes them.
+			text := layout.String()
+			if !strings.HasSuffix(text, ".") && !strings.HasSuffix(text, ",") {
+				return ""
+			}
+			layout.WriteString(strings.Repeat("0", element.field.width))
+			continue
+		}
+
+		chunk, ok := element.field.goLayout()
+		if !ok {
+			return ""
+		}
+		layout.WriteString(chunk)
+	}
+
+	result := layout.String()
+	for _, probe := range goLayoutProbes {
+		if probe.Format(result) != f.formatElements(probe) {
+			return ""
+		}
+	}
+	return result
+}
+
+func (f *compiledFormat) goLayout(format string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	if f.layout == "" {
+		return "", fmt.Errorf("%w: %q", ErrNoGoLayout, format)
+	}
+	return f.layout, nil
+}
+
+// isLayoutSafe returns true if time.Format writes literal as is.
+func isLayoutSafe(literal string) bool {
+	if strings.ContainsAny(literal, "0123456789") {
+		return false
+	}
+	for _, element := range []string{"Jan", "Mon", "MST", "PM", "pm"} {
+		if strings.Contains(literal, element) {
+			return false
+		}
+	}
+	return true
+}
+
+type goLayoutKey struct {
+	kind formatKind
+	pad  byte
+}
+
+// goLayoutElements maps fields to the Go layout elements that format them the same way.
+var goLayoutElements = map[goLayoutKey]string{
+	{fieldYear, '0'}:       "2006",
+	{fieldYear2, '0'}:      "06",
+	{fieldMonth, '0'}:      "01",
+	{fieldMonth, 0}:        "1",
+	{fieldMonthName, 0}:    "January",
+	{fieldMonthShort, 0}:   "Jan",
+	{fieldDay, '0'}:        "02",
+	{fieldDay, ' '}:        "_2",
+	{fieldDay, 0}:          "2",
+	{fieldYearDay, '0'}:    "002",
+	{fieldYearDay, ' '}:    "__2",
+	{fieldWeekdayName, 0}:  "Monday",
+	{fieldWeekdayShort, 0}: "Mon",
+	{fieldHour, '0'}:       "15",
+	{fieldHour12, '0'}:     "03",
+	{fieldHour12, 0}:       "3",
+	{fieldMinute, '0'}:     "04",
+	{fieldMinute, 0}:       "4",
+	{fieldSecond, '0'}:     "05",
+	{fieldSecond, 0}:       "5",
+	{fieldAMPM, 0}:         "PM",
+	{fieldAMPMLower, 0}:    "pm",
+	{fieldOffset, 0}:       "-0700",
+	{fieldOffsetColon, 0}:  "-07:00",
+	{fieldOffsetZulu, 0}:   "Z07:00",
+	{fieldZoneName, 0}:     "MST",
+}
+
+func (f formatField) goLayout() (string, bool) {
+	layout, ok := goLayoutElements[goLayoutKey{f.kind, f.pad}]
+	return layout, ok
+}
+
+func (f *compiledFormat) format(t time.Time) string {
+	if f.layout != "" {
+		return t.Format(f.layout)
+	}
+	return f.formatElements(t)
+}
+
+func (f *compiledFormat) formatElements(t time.Time) string {
+	var builder strings.Builder
+	for _, element := range f.elements {
+		if element.isField {
+			builder.WriteString(element.field.format(t))
+		} else {
+			builder.WriteString(element.literal)
+		}
+	}
+	return builder.String()
+}
+
+func (f formatField) format(t time.Time) string {
+	switch f.kind {
+	case fieldMonthName:
+		return t.Month().String()
+	case fieldMonthShort:
+		return t.Month().String()[:3]
+	case fieldWeekdayName:
+		return t.Weekday().String()
+	case fieldWeekdayShort:
+		return t.Weekday().String()[:3]
+	case fieldWeekdayMin:
+		return t.Weekday().String()[:2]
+	case fieldDayOrdinal:
+		return strconv.Itoa(t.Day()) + ordinalSuffix(t.Day())
+	case fieldFraction:
+		return fmt.Sprintf("%09d", t.Nanosecond())[:f.width]
+	case fieldAMPM:
+		return t.Format("PM")
+	case fieldAMPMLower:
+		return t.Format("pm")
+	case fieldOffset:
+		return t.Format("-0700")
+	case fieldOffsetColon:
+		return t.Format("-07:00")
+	case fieldOffsetZulu:
+		return t.Format("Z07:00")
+	case fieldZoneName:
+		return t.Format("MST")
+	case fieldUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case fieldUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return f.padNumber(fieldNumber(t, f.kind))
+	}
+}
+
+func (f formatField) padNumber(number int) string {
+	text := strconv.Itoa(number)
+	if f.pad != 0 && len(text) < f.width {
+		text = strings.Repeat(string(f.pad), f.width-len(text)) + text
+	}
+	return text
+}
+
+func fieldNumber(t time.Time, kind formatKind) int {
+	isoYear, isoWeek := t.ISOWeek()
+	yearDay := t.YearDay() - 1
+	weekday := int(t.Weekday())
+
+	switch kind {
+	case fieldYear:
+		return t.Year()
+	case fieldYear2:
+		return (t.Year()%100 + 100) % 100
+	case fieldCentury:
+		return t.Year() / 100
+	case fieldQuarter:
+		return QuarterOf(t)
+	case fieldMonth:
+		return int(t.Month())
+	case fieldDay:
+		return t.Day()
+	case fieldYearDay:
+		return yearDay + 1
+	case fieldWeekday:
+		return weekday
+	case fieldISOWeekday:
+		return isoWeekday(t.Weekday())
+	case fieldISOYear:
+		return isoYear
+	case fieldISOYear2:
+		return (isoYear%100 + 100) % 100
+	case fieldISOWeek:
+		return isoWeek
+	case fieldWeekOfYearSunday:
+		return (yearDay + 7 - weekday) / 7
+	case fieldWeekOfYearMonday:
+		return (yearDay + 7 - (weekday+6)%7) / 7
+	case fieldHour:
+		return t.Hour()
+	case fieldHour12:
+		return (t.Hour()+11)%12 + 1
+	case fieldMinute:
+		return t.Minute()
+	default:
+		return t.Second()
+	}
+}
+
+func ordinalSuffix(number int) string {
+	if number%100 >= 11 && number%100 <= 13 {
+		return "th"
+	}
+	switch number % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	default:
+		return "th"
+	}
+}
+
+func (f *compiledFormat) parse(value string, loc *time.Location) (time.Time, error) {
+	if f.err != nil {
+		return time.Time{}, f.err
+	}
+	if f.layout != "" {
+		parsed, err := time.ParseInLocation(f.layout, value, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %w", ErrUnrecognizedDate, err)
+		}
+		return parsed, nil
+	}
+
+	fields := parsedFields{}
+	rest := value
+	for _, element := range f.elements {
+		var err error
+		if element.isField {
+			rest, err = fields.parse(rest, element.field)
+		} else if !strings.HasPrefix(rest, element.literal) {
+			err = fmt.Errorf("expected %q at %q", element.literal, rest)
+		} else {
+			rest = rest[len(element.literal):]
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %q: %w", ErrUnrecognizedDate, value, err)
+		}
+	}
+	if rest != "" {
+		return time.Time{}, fmt.Errorf("%w: %q: extra text %q", ErrUnrecognizedDate, value, rest)
+	}
+
+	parsed, err := fields.time(loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q: %w", ErrUnrecognizedDate, value, err)
+	}
+	return parsed, nil
+}
+
+// parsedFields holds the values read by a parse, indexed by formatKind. Names are stored under their numeric kind:
+// month names as fieldMonth, weekdays as fieldWeekday (Sunday is 0) and am/pm as fieldAMPM (1 for PM).
+type parsedFields struct {
+	values   [fieldCount]int
+	seen     [fieldCount]bool
+	unix     int64
+	zoneName string
+}
+
+func (p *parsedFields) set(kind formatKind, value int) {
+	p.values[kind] = value
+	p.seen[kind] = true
+}
+
+// maxDigits is the number of digits read for each numeric field.
+var maxDigits = map[formatKind]int{
+	fieldYear: 4, fieldYear2: 2, fieldCentury: 2, fieldQuarter: 1, fieldMonth: 2, fieldDay: 2, fieldDayOrdinal: 2,
+	fieldYearDay: 3, fieldWeekday: 1, fieldISOWeekday: 1, fieldISOYear: 4, fieldISOYear2: 2, fieldISOWeek: 2,
+	fieldWeekOfYearSunday: 2, fieldWeekOfYearMonday: 2, fieldHour: 2, fieldHour12: 2, fieldMinute: 2, fieldSecond: 2,
+}
+
+func (p *parsedFields) parse(value string, field formatField) (string, error) {
+	switch field.kind {
+	case fieldMonthName, fieldMonthShort, fieldWeekdayName, fieldWeekdayShort, fieldWeekdayMin:
+		return p.parseName(value, field.kind)
+	case fieldAMPM, fieldAMPMLower:
+		if len(value) < 2 || !strings.EqualFold(value[:2], "AM") && !strings.EqualFold(value[:2], "PM") {
+			return value, fmt.Errorf("expected AM or PM at %q", value)
+		}
+		p.set(fieldAMPM, boolInt(strings.EqualFold(value[:2], "PM")))
+		return value[2:], nil
+	case fieldOffset, fieldOffsetColon, fieldOffsetZulu:
+		return p.parseOffset(value)
+	case fieldZoneName:
+		end := strings.IndexFunc(value, func(r rune) bool { return (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') })
+		if end < 0 {
+			end = len(value)
+		}
+		if end == 0 {
+			return value, fmt.Errorf("expected a time zone name at %q", value)
+		}
+		p.zoneName = value[:end]
+		return value[end:], nil
+	case fieldUnix, fieldUnixMilli:
+		return p.parseUnix(value, field.kind)
+	case fieldFraction:
+		digits, rest := leadingDigits(value, field.width)
+		if digits == "" {
+			return value, fmt.Errorf("expected a fraction at %q", value)
+		}
+		nanoseconds, err := strconv.Atoi(digits + strings.Repeat("0", 9-len(digits)))
+		if err != nil {
+			return value, err
+		}
+		p.set(fieldFraction, nanoseconds)
+		return rest, nil
+	default:
+		return p.parseNumber(value, field)
+	}
+}
+
+func (p *parsedFields) parseNumber(value string, field formatField) (string, error) {
+	if field.pad == ' ' {
+		value = strings.TrimLeft(value, " ")
+	}
+	digits, rest := leadingDigits(value, maxDigits[field.kind])
+	if digits == "" {
+		return value, fmt.Errorf("expected a number at %q", value)
+	}
+	number, err := strconv.Atoi(digits)
+	if err != nil {
+		return value, err
+	}
+
+	switch field.kind {
+	case fieldDayOrdinal:
+		suffix := ordinalSuffix(number)
+		if len(rest) < 2 || !strings.EqualFold(rest[:2], suffix) {
+			return value, fmt.Errorf("expected %q after %d", suffix, number)
+		}
+		p.set(fieldDay, number)
+		return rest[2:], nil
+	case fieldISOWeekday:
+		p.set(fieldWeekday, number%7)
+	default:
+		p.set(field.kind, number)
+	}
+	return rest, nil
+}
+
+// nameLengths is the length of abbreviated names, or zero for full names.
+var nameLengths = map[formatKind]int{
+	fieldMonthName: 0, fieldMonthShort: 3, fieldWeekdayName: 0, fieldWeekdayShort: 3, fieldWeekdayMin: 2,
+}
+
+// parseName matches an English month or weekday name case-insensitively, storing it as fieldMonth or fieldWeekday.
+func (p *parsedFields) parseName(value string, kind formatKind) (string, error) {
+	names := make([]string, 0, 12)
+	target := fieldWeekday
+	if kind == fieldMonthName || kind == fieldMonthShort {
+		target = fieldMonth
+		for month := time.January; month <= time.December; month++ {
+			names = append(names, month.String())
+		}
+	} else {
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			names = append(names, weekday.String())
+		}
+	}
+
+	for index, name := range names {
+		if length := nameLengths[kind]; length > 0 {
+			name = name[:length]
+		}
+		if len(value) >= len(name) && strings.EqualFold(value[:len(name)], name) {
+			if target == fieldMonth {
+				index++
+			}
+			p.set(target, index)
+			return value[len(name):], nil
+		}
+	}
+	return value, fmt.Errorf("expected a name at %q", value)
+}
+
+func (p *parsedFields) parseOffset(value string) (string, error) {
+	if strings.HasPrefix(value, "Z") {
+		p.set(fieldOffset, 0)
+		return value[1:], nil
+	}
+	if value == "" || value[0] != '+' && value[0] != '-' {
+		return value, fmt.Errorf("expected a UTC offset at %q", value)
+	}
+
+	hours, rest := leadingDigits(value[1:], 2)
+	if len(hours) != 2 {
+		return value, fmt.Errorf("expected a UTC offset at %q", value)
+	}
+	minutes, after := leadingDigits(strings.TrimPrefix(rest, ":"), 2)
+	if len(minutes) == 2 {
+		rest = after
+	} else {
+		minutes = "00"
+	}
+
+	hour, err := strconv.Atoi(hours)
+	if err != nil {
+		return value, err
+	}
+	minute, err := strconv.Atoi(minutes)
+	if err != nil {
+		return value, err
+	}
+	offset := hour*3600 + minute*60
+	if value[0] == '-' {
+		offset = -offset
+	}
+	p.set(fieldOffset, offset)
+	return rest, nil
+}
+
+func (p *parsedFields) parseUnix(value string, kind formatKind) (string, error) {
+	negative := strings.HasPrefix(value, "-")
+	digits, rest := leadingDigits(strings.TrimPrefix(value, "-"), 19)
+	if digits == "" {
+		return value, fmt.Errorf("expected a Unix time at %q", value)
+	}
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return value, err
+	}
+	if negative {
+		units = -units
+	}
+
+	p.unix = units * int64(time.Second)
+	if kind == fieldUnixMilli {
+		p.unix = units * int64(time.Millisecond)
+	}
+	p.seen[fieldUnix] = true
+	return rest, nil
+}
+
+func leadingDigits(value string, limit int) (string, string) {
+	end := 0
+	for end < len(value) && end < limit && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	return value[:end], value[end:]
+}
+
+func boolInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// time assembles the parsed fields into a time in loc, or in the parsed UTC offset or zone.
+func (p *parsedFields) time(loc *time.Location) (time.Time, error) {
+	if p.seen[fieldUnix] {
+		return time.Unix(0, p.unix).In(loc), nil
+	}
+
+	date, err := p.date()
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, err := p.hour()
+	if err != nil {
+		return time.Time{}, err
+	}
+	minute, second := p.values[fieldMinute], p.values[fieldSecond]
+	if minute > 59 || second > 59 {
+		return time.Time{}, errors.New("minute or second out of range")
+	}
+	clock := TimeOfDay{Hour: hour, Minute: minute, Second: second, Nanosecond: p.values[fieldFraction]}
+
+	switch {
+	case p.seen[fieldOffset]:
+		offset := p.values[fieldOffset]
+		parsed := date.At(clock, time.UTC).Add(-time.Duration(offset) * time.Second)
+		if _, localOffset := parsed.In(loc).Zone(); localOffset == offset {
+			return parsed.In(loc), nil
+		}
+		return parsed.In(time.FixedZone(p.zoneName, offset)), nil
+	case p.zoneName == "UTC" || p.zoneName == "GMT" || p.zoneName == "Z":
+		return date.At(clock, time.UTC), nil
+	case p.zoneName != "":
+		parsed := date.At(clock, loc)
+		if name, _ := parsed.Zone(); name == p.zoneName {
+			return parsed, nil
+		}
+		return date.At(clock, time.FixedZone(p.zoneName, 0)), nil
+	default:
+		return date.At(clock, loc), nil
+	}
+}
+
+func (p *parsedFields) date() (Date, error) {
+	year := p.year()
+	weekday := time.Weekday(p.values[fieldWeekday])
+
+	switch {
+	case p.seen[fieldISOWeek]:
+		isoYear := year
+		if p.seen[fieldISOYear] {
+			isoYear = p.values[fieldISOYear]
+		} else if p.seen[fieldISOYear2] {
+			isoYear = twoDigitYear(p.values[fieldISOYear2])
+		}
+		if !p.seen[fieldWeekday] {
+			weekday = time.Monday
+		}
+		if week := p.values[fieldISOWeek]; week < 1 || week > ISOWeeksInYear(isoYear) {
+			return Date{}, fmt.Errorf("ISO week %d out of range", week)
+		}
+		return DateOf(DateFromISOWeek(isoYear, p.values[fieldISOWeek], weekday, time.UTC)), nil
+	case p.seen[fieldYearDay]:
+		yearDay := p.values[fieldYearDay]
+		if yearDay < 1 || yearDay > NewDate(year, time.December, 31).YearDay() {
+			return Date{}, fmt.Errorf("day of year %d out of range", yearDay)
+		}
+		return NewDate(year, time.January, yearDay), nil
+	case (p.seen[fieldWeekOfYearSunday] || p.seen[fieldWeekOfYearMonday]) && p.seen[fieldWeekday] &&
+		!p.seen[fieldMonth] && !p.seen[fieldDay]:
+		january1 := NewDate(year, time.January, 1)
+		if p.seen[fieldWeekOfYearSunday] {
+			firstSunday := daysSinceWeekStart(time.Sunday, january1.Weekday())
+			return january1.AddDays(firstSunday + (p.values[fieldWeekOfYearSunday]-1)*7 + int(weekday)), nil
+		}
+		firstMonday := daysSinceWeekStart(time.Monday, january1.Weekday())
+		return january1.AddDays(firstMonday + (p.values[fieldWeekOfYearMonday]-1)*7 +
+			daysSinceWeekStart(weekday, time.Monday)), nil
+	}
+
+	month := 1
+	switch {
+	case p.seen[fieldMonth]:
+		month = p.values[fieldMonth]
+	case p.seen[fieldQuarter]:
+		month = (p.values[fieldQuarter]-1)*3 + 1
+	}
+	day := 1
+	if p.seen[fieldDay] {
+		day = p.values[fieldDay]
+	}
+
+	date := Date{Year: year, Month: time.Month(month), Day: day}
+	if !date.IsValid() {
+		return Date{}, fmt.Errorf("month %d or day %d out of range", month, day)
+	}
+	return date, nil
+}
+
+func (p *parsedFields) year() int {
+	switch {
+	case p.seen[fieldYear]:
+		return p.values[fieldYear]
+	case p.seen[fieldYear2] && p.seen[fieldCentury]:
+		return p.values[fieldCentury]*100 + p.values[fieldYear2]
+	case p.seen[fieldYear2]:
+		return twoDigitYear(p.values[fieldYear2])
+	default:
+		return p.values[fieldCentury] * 100
+	}
+}
+
+// twoDigitYear places a two-digit year in 1969-2068, like time.Parse.
+func twoDigitYear(year int) int {
+	if year >= 69 {
+		return 1900 + year
+	}
+	return 2000 + year
+}
+
+func (p *parsedFields) hour() (int, error) {
+	pm := p.seen[fieldAMPM] && p.values[fieldAMPM] == 1
+
+	if p.seen[fieldHour12] {
+		hour := p.values[fieldHour12]
+		if hour < 1 || hour > 12 {
+			return 0, fmt.Errorf("hour %d out of range", hour)
+		}
+		return hour%12 + 12*boolInt(pm), nil
+	}
+
+	hour := p.values[fieldHour]
+	if hour > 23 {
+		return 0, fmt.Errorf("hour %d out of range", hour)
+	}
+	if pm && hour < 12 {
+		hour += 12
+	}
+	return hour, nil
+}
@@ -0,0 +1,268 @@
+package dateutils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatStrftime(t *testing.T) {
+	// Wednesday, March 6th, 2024, the 66th day of the year, in ISO week 10
+	moment := time.Date(2024, 3, 6, 14, 5, 9, 123456789, time.FixedZone("CET", 3600))
+
+	for _, testCase := range []struct {
+		format   string
+		expected string
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2024-03-06 14:05:09"},
+		{"%F %T", "2024-03-06 14:05:09"},
+		{"%a %A %b %B %h", "Wed Wednesday Mar March Mar"},
+		{"%-d/%-m/%y %-I:%M %p %P", "6/3/24 2:05 PM pm"},
+		{"%e|%k|%l", " 6|14| 2"},
+		{"%j %-j", "066 66"},
+		{"%G-W%V-%u", "2024-W10-3"},
+		{"%U %W %w", "09 10 3"},
+		{"%C %g %q", "20 24 1"},
+		{"%S.%f %N", "09.123456 123456789"},
+		{"%z %:z %Z", "+0100 +01:00 CET"},
+		{"%s", "1709730309"},
+		{"%c", "Wed Mar  6 14:05:09 2024"},
+		{"100%% %n%t", "100% \n\t"},
+		{"%Q %", "%Q %"},
+	} {
+		t.Run(testCase.format, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.FormatStrftime(moment, testCase.format))
+		})
+	}
+}
+
+func TestFormatTokens(t *testing.T) {
+	moment := time.Date(2024, 3, 6, 14, 5, 9, 123456789, time.FixedZone("CET", 3600))
+
+	for _, testCase := range []struct {
+		format   string
+		expected string
+	}{
+		{"YYYY-MM-DD HH:mm:ss", "2024-03-06 14:05:09"},
+		{"YYYY-MM-DDTHH:mm:ss.SSSZ", "2024-03-06T14:05:09.123+01:00"},
+		{"dddd, MMMM Do YYYY, h:mm:ss a", "Wednesday, March 6th 2024, 2:05:09 pm"},
+		{"ddd dd d E", "Wed We 3 3"},
+		{"D/M/YY H:m:s A", "6/3/24 14:5:9 PM"},
+		{"DDDD DDD", "066 66"},
+		{"GGGG-[W]WW-E", "2024-W10-3"},
+		{"[Q]Q GG W", "Q1 24 10"},
+		{"ZZ z", "+0100 CET"},
+		{"X x", "1709730309 1709730309123"},
+		{"[YYYY] YYYY", "YYYY 2024"},
+		{"YYYY [abc", "2024 abc"},
+	} {
+		t.Run(testCase.format, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.FormatTokens(moment, testCase.format))
+		})
+	}
+
+	for day, expected := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 23: "23rd", 31: "31st"} {
+		assert.Equal(t, expected, dateutils.FormatTokens(time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), "Do"))
+	}
+}
+
+func TestFormatDateFns(t *testing.T) {
+	moment := time.Date(2024, 3, 6, 14, 5, 9, 123456789, time.FixedZone("CET", 3600))
+
+	for _, testCase := range []struct {
+		format   string
+		expected string
+	}{
+		{"yyyy-MM-dd", "2024-03-06"},
+		{"yyyy-MM-dd'T'HH:mm:ss.SSSxxx", "2024-03-06T14:05:09.123+01:00"},
+		{"EEEE, MMMM do yyyy, h:mm:ss aaa", "Wednesday, March 6th 2024, 2:05:09 pm"},
+		{"EEE EEEEEE d i", "Wed We 6 3"},
+		{"d/M/yy H:m:s a", "6/3/24 14:5:9 PM"},
+		{"DDD D y", "066 66 2024"},
+		{"RRRR-'W'II-i", "2024-W10-3"},
+		{"'Q'Q RR I", "Q1 24 10"},
+		{"xx XXX z", "+0100 +01:00 CET"},
+		{"t T", "1709730309 1709730309123"},
+		{"h 'o''clock' ''yy", "2 o'clock '24"},
+		{"yyyy 'abc", "2024 abc"},
+		{"yyyy 'o''clock", "2024 o'clock"},
+	} {
+		t.Run(testCase.format, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.FormatDateFns(moment, testCase.format))
+		})
+	}
+
+	assert.Equal(t, "2024-03-06T13:05:09Z", dateutils.FormatDateFns(moment.UTC(), "yyyy-MM-dd'T'HH:mm:ssXXX"))
+
+	layout, err := dateutils.DateFnsLayout("yyyy-MM-dd'T'HH:mm:ssXXX")
+	assert.NoError(t, err)
+	assert.Equal(t, "2006-01-02T15:04:05Z07:00", layout)
+	layout, err = dateutils.DateFnsLayout("EEEE, MMMM d, yyyy h:mm a")
+	assert.NoError(t, err)
+	assert.Equal(t, "Monday, January 2, 2006 3:04 PM", layout)
+	_, err = dateutils.DateFnsLayout("MMMM do")
+	assert.ErrorIs(t, err, dateutils.ErrNoGoLayout)
+}
+
+func TestFormatLayouts(t *testing.T) {
+	for _, testCase := range []struct {
+		format   string
+		tokens   bool
+		expected string
+		err      error
+	}{
+		{format: "%Y-%m-%d %H:%M", expected: "2006-01-02 15:04"},
+		{format: "%a, %d %b %Y %T %z", expected: "Mon, 02 Jan 2006 15:04:05 -0700"},
+		{format: "%-m/%-d/%y %-I:%M %p", expected: "1/2/06 3:04 PM"},
+		{format: "%H:%M:%S.%f", expected: "15:04:05.000000"},
+		{format: "%Y-%j", expected: "2006-002"},
+		{format: "%Y-W%V", err: dateutils.ErrNoGoLayout},
+		{format: "%d of Jan", err: dateutils.ErrNoGoLayout},
+		{format: "%f", err: dateutils.ErrNoGoLayout},
+		{format: "%Q", err: dateutils.ErrUnknownDirective},
+		{format: "YYYY-MM-DD HH:mm", tokens: true, expected: "2006-01-02 15:04"},
+		{format: "dddd, MMMM D, YYYY h:mm A", tokens: true, expected: "Monday, January 2, 2006 3:04 PM"},
+		{format: "YYYY-MM-DDTHH:mm:ss.SSSZ", tokens: true, expected: "2006-01-02T15:04:05.000-07:00"},
+		{format: "MMMM Do", tokens: true, err: dateutils.ErrNoGoLayout},
+		{format: "GGGG-[W]WW", tokens: true, err: dateutils.ErrNoGoLayout},
+	} {
+		t.Run(testCase.format, func(t *testing.T) {
+			var layout string
+			var err error
+			if testCase.tokens {
+				layout, err = dateutils.TokensLayout(testCase.format)
+			} else {
+				layout, err = dateutils.StrftimeLayout(testCase.format)
+			}
+			if testCase.err != nil {
+				assert.ErrorIs(t, err, testCase.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, layout)
+		})
+	}
+}
+
+func TestParseStrftime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	for _, testCase := range []struct {
+		name     string
+		value    string
+		format   string
+		options  dateutils.DateParseOptions
+		expected time.Time
+	}{
+		{"Go layout", "2024-03-06 14:05", "%Y-%m-%d %H:%M", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 14, 5, 0, 0, time.UTC)},
+		{"Location", "2024-03-06 14:05", "%F %R", dateutils.DateParseOptions{Location: newYork}, time.Date(2024, 3, 6, 14, 5, 0, 0, newYork)},
+		{"Day of year", "2024-066", "%Y-%j", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"ISO week", "2025-W01-1", "%G-W%V-%u", dateutils.DateParseOptions{}, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{"ISO week default weekday", "2024-W10", "%G-W%V", dateutils.DateParseOptions{}, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"Sunday week", "2024 09 Wed", "%Y %U %a", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"Monday week", "2024 10 3", "%Y %W %w", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"Literal digits", "Day 6 of 3/2024 at 2pm", "Day %-d of %-m/%Y at %-I%P", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 14, 0, 0, 0, time.UTC)},
+		{"Fraction", "14:05:09.123456 day 66 of 2024", "%H:%M:%S.%f day %-j of %Y", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 14, 5, 9, 123456000, time.UTC)},
+		{"Offset", "2024-066 14:05 -05:00", "%Y-%j %H:%M %:z", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 14, 5, 0, 0, time.FixedZone("", -18000))},
+		{"Offset matching location", "2024-066 14:05 -0500", "%Y-%j %H:%M %z", dateutils.DateParseOptions{Location: newYork}, time.Date(2024, 3, 6, 14, 5, 0, 0, newYork)},
+		{"Two-digit year and century", "20 24 066", "%C %y %j", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"Unix", "1709730309", "%s", dateutils.DateParseOptions{}, time.Date(2024, 3, 6, 13, 5, 9, 0, time.UTC)},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			parsed, err := dateutils.ParseStrftime(testCase.value, testCase.format, testCase.options)
+			assert.NoError(t, err)
+			assert.True(t, testCase.expected.Equal(parsed), "got %s", parsed)
+			assert.Equal(t, testCase.expected.Location().String(), parsed.Location().String())
+		})
+	}
+}
+
+func TestParseStrftimeErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		value  string
+		format string
+		err    error
+	}{
+		{"2024-13-01", "%Y-%m-%d", dateutils.ErrUnrecognizedDate},
+		{"2023-366", "%Y-%j", dateutils.ErrUnrecognizedDate},
+		{"2024-W54", "%G-W%V", dateutils.ErrUnrecognizedDate},
+		{"2024-066 extra", "%Y-%j", dateutils.ErrUnrecognizedDate},
+		{"Day x", "Day %-d", dateutils.ErrUnrecognizedDate},
+		{"2024", "%Q", dateutils.ErrUnknownDirective},
+	} {
+		t.Run(testCase.value, func(t *testing.T) {
+			_, err := dateutils.ParseStrftime(testCase.value, testCase.format)
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		value    string
+		format   string
+		expected time.Time
+	}{
+		{"Go layout", "2024-03-06 14:05", "YYYY-MM-DD HH:mm", time.Date(2024, 3, 6, 14, 5, 0, 0, time.UTC)},
+		{"Ordinal", "March 6th 2024, 2:05 pm", "MMMM Do YYYY, h:mm a", time.Date(2024, 3, 6, 14, 5, 0, 0, time.UTC)},
+		{"Ordinal teen", "Mar 13th 24", "MMM Do YY", time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"Day of year", "2024.066", "YYYY.DDDD", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"ISO week", "2024-W10-3", "GGGG-[W]WW-E", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"Unix milliseconds", "1709730309123", "x", time.Date(2024, 3, 6, 13, 5, 9, 123000000, time.UTC)},
+		{"Hour without padding", "6/3/2024 9:05:09.5Z", "D/M/YYYY H:mm:ss.SZ", time.Date(2024, 3, 6, 9, 5, 9, 500000000, time.UTC)},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			parsed, err := dateutils.ParseTokens(testCase.value, testCase.format)
+			assert.NoError(t, err)
+			assert.True(t, testCase.expected.Equal(parsed), "got %s", parsed)
+		})
+	}
+
+	_, err := dateutils.ParseTokens("March 6nd 2024", "MMMM Do YYYY")
+	assert.ErrorIs(t, err, dateutils.ErrUnrecognizedDate)
+}
+
+func TestParseDateFns(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		value    string
+		format   string
+		expected time.Time
+	}{
+		{"Go layout", "2024-03-06", "yyyy-MM-dd", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"RFC 3339", "2024-03-06T14:05:09Z", "yyyy-MM-dd'T'HH:mm:ssXXX", time.Date(2024, 3, 6, 14, 5, 9, 0, time.UTC)},
+		{"Ordinal", "Wednesday, March 6th 2024", "EEEE, MMMM do yyyy", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"Day of year", "2024.066", "yyyy.DDD", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"ISO week", "2024-W10-3", "RRRR-'W'II-i", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"Quoted quote", "6 o'clock", "h 'o''clock'", time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			parsed, err := dateutils.ParseDateFns(testCase.value, testCase.format)
+			assert.NoError(t, err)
+			assert.True(t, testCase.expected.Equal(parsed), "got %s", parsed)
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	formats := []string{"%Y-%j %H:%M:%S", "%G-W%V-%u %I%p", "%d %B %Y %-H:%M", "%Y %U %a %T"}
+	start := time.Date(2023, 12, 25, 5, 30, 0, 0, time.UTC)
+
+	for day := range 400 {
+		moment := start.AddDate(0, 0, day)
+		for _, format := range formats {
+			text := dateutils.FormatStrftime(moment, format)
+			parsed, err := dateutils.ParseStrftime(text, format)
+			assert.NoError(t, err, text)
+			expected := moment
+			if format == "%G-W%V-%u %I%p" {
+				expected = moment.Truncate(time.Hour)
+			}
+			assert.True(t, expected.Equal(parsed), "%s parsed as %s", text, parsed)
+		}
+	}
+}
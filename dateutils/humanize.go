@@ -0,0 +1,492 @@
+package dateutils
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMessageCatalog is returned when a message catalog is missing required messages.
+var ErrInvalidMessageCatalog = errors.New("dateutils: invalid message catalog")
+
+// UnitMessages - the messages for one unit, one per CLDR plural category. "{n}" is replaced by the count.
+type UnitMessages struct {
+	Zero    string `json:"zero"`
+	One     string `json:"one"`
+	Two     string `json:"two"`
+	Few     string `json:"few"`
+	Many    string `json:"many"`
+	Other   string `json:"other"`
+	Compact string `json:"compact"`
+}
+
+// MessageCatalog - the messages used by RelativeTime and HumanizeDuration in one language.
+// Now is used for times within the Now threshold of the reference time; Past and Future wrap a formatted
+// duration, replacing "{time}". Units is keyed by unit name ("second", "minute", "hour", "day", "week", "month"
+// and "year"). Plural is the language code whose CLDR plural rule picks the unit message for a count, and defaults
+// to "en", where One is used for a count of 1 and Other for every other count. Compact is used by compact
+// formatting and defaults to Other.
+type MessageCatalog struct {
+	Plural           string                  `json:"plural"`
+	Now              string                  `json:"now"`
+	Past             string                  `json:"past"`
+	Future           string                  `json:"future"`
+	Units            map[string]UnitMessages `json:"units"`
+	Separator        string                  `json:"separator"`
+	LastSeparator    string                  `json:"lastSeparator"`
+	CompactSeparator string                  `json:"compactSeparator"`
+}
+
+// EnglishCatalog is the default message catalog.
+var EnglishCatalog = MessageCatalog{
+	Now:    "just now",
+	Past:   "{time} ago",
+	Future: "in {time}",
+	Units: map[string]UnitMessages{
+		"second": {One: "1 second", Other: "{n} seconds", Compact: "{n}s"},
+		"minute": {One: "1 minute", Other: "{n} minutes", Compact: "{n}m"},
+		"hour":   {One: "1 hour", Other: "{n} hours", Compact: "{n}h"},
+		"day":    {One: "1 day", Other: "{n} days", Compact: "{n}d"},
+		"week":   {One: "1 week", Other: "{n} weeks", Compact: "{n}w"},
+		"month":  {One: "1 month", Other: "{n} months", Compact: "{n}mo"},
+		"year":   {One: "1 year", Other: "{n} years", Compact: "{n}y"},
+	},
+	Separator:        ", ",
+	LastSeparator:    " and ",
+	CompactSeparator: " ",
+}
+
+// ParseMessageCatalog reads a message catalog in JSON form, so that languages can be added without code changes:
+//
+//	{"now": "à l'instant", "past": "il y a {time}", "future": "dans {time}", "lastSeparator": " et ",
+//	 "units": {"minute": {"one": "1 minute", "other": "{n} minutes", "compact": "{n} min"}, ...}}
+//
+// Languages with more plural forms name their plural rule and give a message for each of its categories:
+//
+//	{"plural": "pl", ..., "units": {"minute": {"one": "1 minuta", "few": "{n} minuty", "many": "{n} minut",
+//	 "other": "{n} minuty"}, ...}}
+//
+// Every message except the separators and compact unit messages is required, including one message for each
+// category of the plural rule. See PluralLanguages for the supported plural rules.
+func ParseMessageCatalog(r io.Reader) (*MessageCatalog, error) {
+	var catalog MessageCatalog
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMessageCatalog, err)
+	}
+
+	if err := catalog.validate(); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// LoadMessageCatalog reads a message catalog in JSON form from a local file. See ParseMessageCatalog.
+func LoadMessageCatalog(path string) (*MessageCatalog, error) {
+	file, err := os.Open(path) //nolint:gosec // the path is supplied by the caller on purpose
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseMessageCatalog(file)
+}
+
+func (c *MessageCatalog) validate() error {
+	if c.Now == "" || c.Past == "" || c.Future == "" {
+		return fmt.Errorf("%w: now, past and future messages are required", ErrInvalidMessageCatalog)
+	}
+	rule, ok := pluralRules[cmp.Or(c.Plural, "en")]
+	if !ok {
+		return fmt.Errorf("%w: unknown plural rule %q", ErrInvalidMessageCatalog, c.Plural)
+	}
+	for _, unit := range humanizeUnits {
+		messages := c.Units[unit.String()]
+		for _, category := range rule.categories {
+			if messages.message(category) == "" {
+				return fmt.Errorf("%w: missing %q message for unit %q", ErrInvalidMessageCatalog, category, unit)
+			}
+		}
+	}
+	return nil
+}
+
+// pluralCategory is a CLDR plural category.
+type pluralCategory string
+
+const (
+	pluralZero  pluralCategory = "zero"
+	pluralOne   pluralCategory = "one"
+	pluralTwo   pluralCategory = "two"
+	pluralFew   pluralCategory = "few"
+	pluralMany  pluralCategory = "many"
+	pluralOther pluralCategory = "other"
+)
+
+// pluralRule is the CLDR cardinal plural rule of a language for non-negative integer counts.
+type pluralRule struct {
+	categories []pluralCategory
+	category   func(count int64) pluralCategory
+}
+
+var (
+	otherOnlyRule = pluralRule{
+		categories: []pluralCategory{pluralOther},
+		category:   func(int64) pluralCategory { return pluralOther },
+	}
+	oneOtherRule = pluralRule{
+		categories: []pluralCategory{pluralOne, pluralOther},
+		category: func(count int64) pluralCategory {
+			return pluralIf(count == 1, pluralOne, pluralOther)
+		},
+	}
+	frenchRule = pluralRule{
+		categories: []pluralCategory{pluralOne, pluralOther},
+		category: func(count int64) pluralCategory {
+			return pluralIf(count <= 1, pluralOne, pluralOther)
+		},
+	}
+	eastSlavicRule = pluralRule{
+		categories: []pluralCategory{pluralOne, pluralFew, pluralMany},
+		category: func(count int64) pluralCategory {
+			switch {
+			case count%10 == 1 && count%100 != 11:
+				return pluralOne
+			case isPluralFew(count):
+				return pluralFew
+			default:
+				return pluralMany
+			}
+		},
+	}
+	polishRule = pluralRule{
+		categories: []pluralCategory{pluralOne, pluralFew, pluralMany},
+		category: func(count int64) pluralCategory {
+			switch {
+			case count == 1:
+				return pluralOne
+			case isPluralFew(count):
+				return pluralFew
+			default:
+				return pluralMany
+			}
+		},
+	}
+	czechRule = pluralRule{
+		categories: []pluralCategory{pluralOne, pluralFew, pluralOther},
+		category: func(count int64) pluralCategory {
+			switch {
+			case count == 1:
+				return pluralOne
+			case count >= 2 && count <= 4:
+				return pluralFew
+			default:
+				return pluralOther
+			}
+		},
+	}
+	arabicRule = pluralRule{
+		categories: []pluralCategory{pluralZero, pluralOne, pluralTwo, pluralFew, pluralMany, pluralOther},
+		category: func(count int64) pluralCategory {
+			switch {
+			case count <= 2:
+				return []pluralCategory{pluralZero, pluralOne, pluralTwo}[count]
+			case count%100 >= 3 && count%100 <= 10:
+				return pluralFew
+			case count%100 >= 11:
+				return pluralMany
+			default:
+				return pluralOther
+			}
+		},
+	}
+)
+
+// pluralRules maps language codes to their plural rules.
+var pluralRules = map[string]pluralRule{
+	"en": oneOtherRule, "de": oneOtherRule, "nl": oneOtherRule, "sv": oneOtherRule, "da": oneOtherRule,
+	"nb": oneOtherRule, "fi": oneOtherRule, "et": oneOtherRule, "el": oneOtherRule, "hu": oneOtherRule,
+	"tr": oneOtherRule, "bg": oneOtherRule, "es": oneOtherRule, "it": oneOtherRule, "pt-PT": oneOtherRule,
+	"fr": frenchRule, "pt": frenchRule,
+	"ru": eastSlavicRule, "uk": eastSlavicRule, "be": eastSlavicRule,
+	"pl": polishRule,
+	"cs": czechRule, "sk": czechRule,
+	"ar": arabicRule,
+	"ja": otherOnlyRule, "zh": otherOnlyRule, "ko": otherOnlyRule, "vi": otherOnlyRule, "th": otherOnlyRule,
+	"id": otherOnlyRule,
+}
+
+// PluralLanguages returns the language codes with a supported plural rule, for use as MessageCatalog.Plural.
+func PluralLanguages() []string {
+	return slices.Sorted(maps.Keys(pluralRules))
+}
+
+func pluralIf(condition bool, yes, no pluralCategory) pluralCategory {
+	if condition {
+		return yes
+	}
+	return no
+}
+
+// isPluralFew reports whether count ends in 2, 3 or 4 but not in 12, 13 or 14.
+func isPluralFew(count int64) bool {
+	return count%10 >= 2 && count%10 <= 4 && (count%100 < 12 || count%100 > 14)
+}
+
+func (m UnitMessages) message(category pluralCategory) string {
+	switch category {
+	case pluralZero:
+		return m.Zero
+	case pluralOne:
+		return m.One
+	case pluralTwo:
+		return m.Two
+	case pluralFew:
+		return m.Few
+	case pluralMany:
+		return m.Many
+	default:
+		return m.Other
+	}
+}
+
+// HumanizeThresholds - the counts at which a single-unit result moves to the next larger unit, so that 50 minutes
+// reads "1 hour" rather than "50 minutes". Zero fields use the defaults.
+type HumanizeThresholds struct {
+	Now    time.Duration
+	Second int
+	Minute int
+	Hour   int
+	Day    int
+	Week   int
+	Month  int
+}
+
+// HumanizeOptions - relative time and duration formatting options.
+type HumanizeOptions struct {
+	Precision  int
+	Compact    bool
+	MinUnit    Unit
+	MaxUnit    Unit
+	Thresholds HumanizeThresholds
+	Catalog    *MessageCatalog
+}
+
+func parseHumanizeOptions(opts ...HumanizeOptions) HumanizeOptions {
+	options := HumanizeOptions{
+		MinUnit: Second,
+		MaxUnit: Year,
+		Thresholds: HumanizeThresholds{
+			Now:    10 * time.Second,
+			Second: 45,
+			Minute: 45,
+			Hour:   22,
+			Day:    7,
+			Week:   4,
+			Month:  11,
+		},
+		Catalog: &EnglishCatalog,
+	}
+
+	for _, opt := range opts {
+		if opt.Precision > 0 {
+			options.Precision = opt.Precision
+		}
+		if opt.Compact {
+			options.Compact = true
+		}
+		if opt.MinUnit != 0 {
+			options.MinUnit = opt.MinUnit
+		}
+		if opt.MaxUnit != 0 {
+			options.MaxUnit = opt.MaxUnit
+		}
+		if opt.Thresholds.Now != 0 {
+			options.Thresholds.Now = opt.Thresholds.Now
+		}
+		if opt.Thresholds.Second != 0 {
+			options.Thresholds.Second = opt.Thresholds.Second
+		}
+		if opt.Thresholds.Minute != 0 {
+			options.Thresholds.Minute = opt.Thresholds.Minute
+		}
+		if opt.Thresholds.Hour != 0 {
+			options.Thresholds.Hour = opt.Thresholds.Hour
+		}
+		if opt.Thresholds.Day != 0 {
+			options.Thresholds.Day = opt.Thresholds.Day
+		}
+		if opt.Thresholds.Week != 0 {
+			options.Thresholds.Week = opt.Thresholds.Week
+		}
+		if opt.Thresholds.Month != 0 {
+			options.Thresholds.Month = opt.Thresholds.Month
+		}
+		if opt.Catalog != nil {
+			options.Catalog = opt.Catalog
+		}
+	}
+
+	return options
+}
+
+// humanizeUnits are the units used for formatting, in ascending order. Months are 30 days and years 365 days long.
+var humanizeUnits = []Unit{Second, Minute, Hour, Day, Week, Month, Year}
+
+var humanizeUnitLengths = map[Unit]time.Duration{
+	Second: time.Second,
+	Minute: time.Minute,
+	Hour:   time.Hour,
+	Day:    24 * time.Hour,
+	Week:   7 * 24 * time.Hour,
+	Month:  30 * 24 * time.Hour,
+	Year:   365 * 24 * time.Hour,
+}
+
+// RelativeTime describes t relative to reference, such as "3 minutes ago", "in 2 days" or "just now".
+// The difference is rounded to the nearest unit; with the default precision of one unit, the thresholds decide when
+// to move to the next larger unit, so 50 minutes ago reads "1 hour ago".
+// RelativeTime also accepts an options object with the following properties:
+//
+//	HumanizeOptions.Precision: the maximum number of units, e.g. 2 for "in 1 day and 3 hours", defaults to 1; values below 1 use the default.
+//	HumanizeOptions.Compact: whether to use compact unit messages such as "3m ago", defaults to false.
+//	HumanizeOptions.MinUnit: the smallest unit used, defaults to Second.
+//	HumanizeOptions.MaxUnit: the largest unit used, defaults to Year.
+//	HumanizeOptions.Thresholds: the rounding thresholds; Now defaults to 10 seconds (negative to disable), and the Second, Minute, Hour, Day, Week and Month counts default to 45, 45, 22, 7, 4 and 11.
+//	HumanizeOptions.Catalog: the messages to use, defaults to EnglishCatalog.
+func RelativeTime(t, reference time.Time, opts ...HumanizeOptions) string {
+	options := parseHumanizeOptions(opts...)
+	if options.Precision == 0 {
+		options.Precision = 1
+	}
+
+	difference := t.Sub(reference)
+	if difference.Abs() < options.Thresholds.Now {
+		return options.Catalog.Now
+	}
+
+	message := options.Catalog.Future
+	if difference < 0 {
+		message = options.Catalog.Past
+	}
+	return strings.ReplaceAll(message, "{time}", humanize(difference.Abs(), options))
+}
+
+// HumanizeDuration formats a duration such as "1 hour and 5 minutes", or "1h 5m" in compact form.
+// The last unit shown is rounded to the nearest whole count, and units with a count of 0 are left out.
+// Negative durations are formatted by their absolute value.
+// HumanizeDuration also accepts an options object with the same properties as RelativeTime; the precision defaults
+// to 2 units and the Now threshold is not used.
+func HumanizeDuration(d time.Duration, opts ...HumanizeOptions) string {
+	options := parseHumanizeOptions(opts...)
+	if options.Precision == 0 {
+		options.Precision = 2
+	}
+	return humanize(d.Abs(), options)
+}
+
+func humanize(d time.Duration, options HumanizeOptions) string {
+	var units []Unit
+	for _, unit := range humanizeUnits {
+		if unit >= options.MinUnit && unit <= options.MaxUnit {
+			units = append(units, unit)
+		}
+	}
+	if len(units) == 0 {
+		units = []Unit{Second}
+	}
+
+	largest := largestUnit(d, units, options)
+	smallest := max(largest-options.Precision+1, 0)
+	rounded := d.Round(humanizeUnitLengths[units[smallest]])
+	if largest+1 < len(units) && rounded >= humanizeUnitLengths[units[largest+1]] && options.Precision > 1 {
+		// rounding carried into the next unit, e.g. 59 minutes 59.6 seconds.
+		largest++
+		smallest++
+		rounded = d.Round(humanizeUnitLengths[units[smallest]])
+	}
+
+	var parts []string
+	for index := largest; index >= smallest; index-- {
+		length := humanizeUnitLengths[units[index]]
+		count := rounded / length
+		rounded -= count * length
+		if count > 0 || (index == smallest && len(parts) == 0) {
+			parts = append(parts, options.Catalog.unitMessage(units[index], int64(count), options.Compact))
+		}
+	}
+	return options.Catalog.join(parts, options.Compact)
+}
+
+// largestUnit returns the index of the largest unit to show: the largest unit that fits in d or, for single-unit
+// results, the first unit whose rounded count stays below its threshold.
+func largestUnit(d time.Duration, units []Unit, options HumanizeOptions) int {
+	if options.Precision == 1 {
+		for index, unit := range units[:len(units)-1] {
+			count := d.Round(humanizeUnitLengths[unit]) / humanizeUnitLengths[unit]
+			if int64(count) < int64(options.Thresholds.of(unit)) {
+				return index
+			}
+		}
+		return len(units) - 1
+	}
+
+	for index := len(units) - 1; index > 0; index-- {
+		if d >= humanizeUnitLengths[units[index]] {
+			return index
+		}
+	}
+	return 0
+}
+
+func (t HumanizeThresholds) of(unit Unit) int {
+	switch unit {
+	case Second:
+		return t.Second
+	case Minute:
+		return t.Minute
+	case Hour:
+		return t.Hour
+	case Day:
+		return t.Day
+	case Week:
+		return t.Week
+	default:
+		return t.Month
+	}
+}
+
+func (c *MessageCatalog) unitMessage(unit Unit, count int64, compact bool) string {
+	messages := c.Units[unit.String()]
+
+	message := messages.Compact
+	if !compact || message == "" {
+		rule, ok := pluralRules[cmp.Or(c.Plural, "en")]
+		if !ok {
+			rule = oneOtherRule
+		}
+		message = cmp.Or(messages.message(rule.category(count)), messages.Other)
+	}
+	return strings.ReplaceAll(message, "{n}", strconv.FormatInt(count, 10))
+}
+
+func (c *MessageCatalog) join(parts []string, compact bool) string {
+	if compact {
+		return strings.Join(parts, cmp.Or(c.CompactSeparator, " "))
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], cmp.Or(c.Separator, ", ")) +
+		cmp.Or(c.LastSeparator, " and ") + parts[len(parts)-1]
+}
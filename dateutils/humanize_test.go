@@ -0,0 +1,178 @@
+package dateutils_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelativeTime(t *testing.T) {
+	reference := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		difference time.Duration
+		options    []dateutils.HumanizeOptions
+		expected   string
+	}{
+		{"now", 0, nil, "just now"},
+		{"within now threshold", -5 * time.Second, nil, "just now"},
+		{"seconds ago", -30 * time.Second, nil, "30 seconds ago"},
+		{"seconds round to a minute", -50 * time.Second, nil, "1 minute ago"},
+		{"minutes ago", -3 * time.Minute, nil, "3 minutes ago"},
+		{"minutes round", -(3*time.Minute + 40*time.Second), nil, "4 minutes ago"},
+		{"minutes round to an hour", -50 * time.Minute, nil, "1 hour ago"},
+		{"in hours", 5 * time.Hour, nil, "in 5 hours"},
+		{"hours round to a day", 23 * time.Hour, nil, "in 1 day"},
+		{"in days", 2 * 24 * time.Hour, nil, "in 2 days"},
+		{"weeks", -15 * 24 * time.Hour, nil, "2 weeks ago"},
+		{"months", 60 * 24 * time.Hour, nil, "in 2 months"},
+		{"years", -400 * 24 * time.Hour, nil, "1 year ago"},
+		{"precision", 27*time.Hour + 10*time.Minute, []dateutils.HumanizeOptions{{Precision: 2}}, "in 1 day and 3 hours"},
+		{"precision 3", -(27*time.Hour + 10*time.Minute), []dateutils.HumanizeOptions{{Precision: 3}}, "1 day, 3 hours and 10 minutes ago"},
+		{"compact", -3 * time.Minute, []dateutils.HumanizeOptions{{Compact: true}}, "3m ago"},
+		{"negative precision", 27*time.Hour + 10*time.Minute, []dateutils.HumanizeOptions{{Precision: -1}}, "in 1 day"},
+		{"max unit", 3 * 24 * time.Hour, []dateutils.HumanizeOptions{{MaxUnit: dateutils.Hour}}, "in 72 hours"},
+		{"min unit", -90 * time.Second, []dateutils.HumanizeOptions{{MinUnit: dateutils.Hour}}, "0 hours ago"},
+		{"custom thresholds", -50 * time.Minute, []dateutils.HumanizeOptions{{Thresholds: dateutils.HumanizeThresholds{Minute: 60}}}, "50 minutes ago"},
+		{"now disabled", 2 * time.Second, []dateutils.HumanizeOptions{{Thresholds: dateutils.HumanizeThresholds{Now: -1}}}, "in 2 seconds"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := dateutils.RelativeTime(reference.Add(testCase.difference), reference, testCase.options...)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		duration time.Duration
+		options  []dateutils.HumanizeOptions
+		expected string
+	}{
+		{"zero", 0, nil, "0 seconds"},
+		{"below a second", 300 * time.Millisecond, nil, "0 seconds"},
+		{"one second", time.Second, nil, "1 second"},
+		{"hour and minutes", time.Hour + 5*time.Minute, nil, "1 hour and 5 minutes"},
+		{"zero units are skipped", time.Hour + 3*time.Second, nil, "1 hour"},
+		{"last unit is rounded", time.Hour + 5*time.Minute + 40*time.Second, nil, "1 hour and 6 minutes"},
+		{"rounding carries", 59*time.Minute + 59*time.Second + 600*time.Millisecond, nil, "1 hour"},
+		{"negative", -90 * time.Second, nil, "1 minute and 30 seconds"},
+		{"compact", time.Hour + 5*time.Minute, []dateutils.HumanizeOptions{{Compact: true}}, "1h 5m"},
+		{"compact precision", 26*time.Hour + 5*time.Minute + 7*time.Second, []dateutils.HumanizeOptions{{Compact: true, Precision: 4}}, "1d 2h 5m 7s"},
+		{"max unit", 26*time.Hour + 5*time.Minute, []dateutils.HumanizeOptions{{Compact: true, MaxUnit: dateutils.Hour}}, "26h 5m"},
+		{"single unit uses thresholds", 50 * time.Minute, []dateutils.HumanizeOptions{{Precision: 1}}, "1 hour"},
+		{"negative precision", 90 * time.Second, []dateutils.HumanizeOptions{{Precision: -1}}, "1 minute and 30 seconds"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.HumanizeDuration(testCase.duration, testCase.options...))
+		})
+	}
+}
+
+const frenchCatalog = `{
+	"plural": "fr",
+	"now": "à l'instant",
+	"past": "il y a {time}",
+	"future": "dans {time}",
+	"lastSeparator": " et ",
+	"units": {
+		"second": {"one": "1 seconde", "other": "{n} secondes", "compact": "{n} s"},
+		"minute": {"one": "1 minute", "other": "{n} minutes", "compact": "{n} min"},
+		"hour": {"one": "1 heure", "other": "{n} heures", "compact": "{n} h"},
+		"day": {"one": "1 jour", "other": "{n} jours"},
+		"week": {"one": "1 semaine", "other": "{n} semaines"},
+		"month": {"one": "1 mois", "other": "{n} mois"},
+		"year": {"one": "1 an", "other": "{n} ans"}
+	}
+}`
+
+func TestMessageCatalog(t *testing.T) {
+	catalog, err := dateutils.ParseMessageCatalog(strings.NewReader(frenchCatalog))
+	assert.NoError(t, err)
+
+	reference := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+	options := dateutils.HumanizeOptions{Catalog: catalog}
+
+	assert.Equal(t, "il y a 3 minutes", dateutils.RelativeTime(reference.Add(-3*time.Minute), reference, options))
+	assert.Equal(t, "dans 1 jour", dateutils.RelativeTime(reference.Add(24*time.Hour), reference, options))
+	assert.Equal(t, "à l'instant", dateutils.RelativeTime(reference, reference, options))
+	assert.Equal(t, "1 heure et 5 minutes", dateutils.HumanizeDuration(65*time.Minute, options))
+	assert.Equal(t, "1 h 5 min", dateutils.HumanizeDuration(65*time.Minute, dateutils.HumanizeOptions{Catalog: catalog, Compact: true}))
+
+	path := filepath.Join(t.TempDir(), "fr.json")
+	assert.NoError(t, os.WriteFile(path, []byte(frenchCatalog), 0o600))
+	loaded, err := dateutils.LoadMessageCatalog(path)
+	assert.NoError(t, err)
+	assert.Equal(t, catalog, loaded)
+
+	invalid := []string{
+		`{`,
+		`{"now": "now", "past": "{time} ago", "future": "in {time}"}`,
+		`{"past": "{time} ago", "future": "in {time}", "units": {}}`,
+		`{"unknown": true}`,
+	}
+	for _, text := range invalid {
+		_, err := dateutils.ParseMessageCatalog(strings.NewReader(text))
+		assert.True(t, errors.Is(err, dateutils.ErrInvalidMessageCatalog), text)
+	}
+
+	_, err = dateutils.LoadMessageCatalog(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+const polishCatalog = `{
+	"plural": "pl",
+	"now": "przed chwilą",
+	"past": "{time} temu",
+	"future": "za {time}",
+	"lastSeparator": " i ",
+	"units": {
+		"second": {"one": "1 sekunda", "few": "{n} sekundy", "many": "{n} sekund"},
+		"minute": {"one": "1 minuta", "few": "{n} minuty", "many": "{n} minut", "compact": "{n} min"},
+		"hour": {"one": "1 godzina", "few": "{n} godziny", "many": "{n} godzin"},
+		"day": {"one": "1 dzień", "few": "{n} dni", "many": "{n} dni"},
+		"week": {"one": "1 tydzień", "few": "{n} tygodnie", "many": "{n} tygodni"},
+		"month": {"one": "1 miesiąc", "few": "{n} miesiące", "many": "{n} miesięcy"},
+		"year": {"one": "1 rok", "few": "{n} lata", "many": "{n} lat"}
+	}
+}`
+
+func TestMessageCatalogPluralRules(t *testing.T) {
+	catalog, err := dateutils.ParseMessageCatalog(strings.NewReader(polishCatalog))
+	assert.NoError(t, err)
+
+	options := dateutils.HumanizeOptions{Catalog: catalog, MinUnit: dateutils.Minute, MaxUnit: dateutils.Minute}
+	for minutes, expected := range map[int]string{
+		0: "0 minut", 1: "1 minuta", 2: "2 minuty", 4: "4 minuty", 5: "5 minut", 12: "12 minut", 21: "21 minut",
+		22: "22 minuty", 104: "104 minuty", 112: "112 minut",
+	} {
+		assert.Equal(t, expected, dateutils.HumanizeDuration(time.Duration(minutes)*time.Minute, options))
+	}
+
+	reference := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "5 lat temu", dateutils.RelativeTime(reference.AddDate(-5, 0, 0), reference, dateutils.HumanizeOptions{Catalog: catalog}))
+	assert.Equal(t, "za 3 godziny", dateutils.RelativeTime(reference.Add(3*time.Hour), reference, dateutils.HumanizeOptions{Catalog: catalog}))
+	assert.Equal(t, "1 godzina i 2 minuty", dateutils.HumanizeDuration(62*time.Minute, dateutils.HumanizeOptions{Catalog: catalog}))
+
+	missingFew := strings.Replace(polishCatalog, `"few": "{n} lata", `, "", 1)
+	_, err = dateutils.ParseMessageCatalog(strings.NewReader(missingFew))
+	assert.ErrorIs(t, err, dateutils.ErrInvalidMessageCatalog)
+
+	unknownRule := strings.Replace(polishCatalog, `"plural": "pl"`, `"plural": "xx"`, 1)
+	_, err = dateutils.ParseMessageCatalog(strings.NewReader(unknownRule))
+	assert.ErrorIs(t, err, dateutils.ErrInvalidMessageCatalog)
+
+	assert.Contains(t, dateutils.PluralLanguages(), "ru")
+}
@@ -0,0 +1,359 @@
+package dateutils
+
+import (
+	"slices"
+	"time"
+)
+
+// IntervalBounds - which endpoints of an Interval are included.
+type IntervalBounds int
+
+const (
+	// ClosedOpen includes the start but not the end: [start, end). It is the zero value.
+	ClosedOpen IntervalBounds = iota
+	// Closed includes both endpoints: [start, end].
+	Closed
+	// OpenClosed includes the end but not the start: (start, end].
+	OpenClosed
+	// Open includes neither endpoint: (start, end).
+	Open
+)
+
+// Interval is a time range with explicit open or closed bounds. The zero Bounds value is half-open, [start, end).
+type Interval struct {
+	Start  time.Time
+	End    time.Time
+	Bounds IntervalBounds
+}
+
+// NewInterval creates an interval between start and end, half-open unless bounds are given.
+func NewInterval(start, end time.Time, bounds ...IntervalBounds) Interval {
+	interval := Interval{Start: start, End: end}
+	if len(bounds) > 0 {
+		interval.Bounds = bounds[0]
+	}
+	return interval
+}
+
+func boundsOf(startClosed, endClosed bool) IntervalBounds {
+	switch {
+	case startClosed && endClosed:
+		return Closed
+	case startClosed:
+		return ClosedOpen
+	case endClosed:
+		return OpenClosed
+	default:
+		return Open
+	}
+}
+
+// StartClosed reports whether the start is included in the interval.
+func (i Interval) StartClosed() bool {
+	return i.Bounds == ClosedOpen || i.Bounds == Closed
+}
+
+// EndClosed reports whether the end is included in the interval.
+func (i Interval) EndClosed() bool {
+	return i.Bounds == Closed || i.Bounds == OpenClosed
+}
+
+// String formats the interval in mathematical notation, e.g. "[2024-01-01T00:00:00Z, 2024-01-02T00:00:00Z)".
+func (i Interval) String() string {
+	open, closing := "(", ")"
+	if i.StartClosed() {
+		open = "["
+	}
+	if i.EndClosed() {
+		closing = "]"
+	}
+	return open + i.Start.Format(time.RFC3339Nano) + ", " + i.End.Format(time.RFC3339Nano) + closing
+}
+
+// IsEmpty returns true if the interval contains no instant.
+func (i Interval) IsEmpty() bool {
+	switch i.Start.Compare(i.End) {
+	case -1:
+		return false
+	case 0:
+		return i.Bounds != Closed
+	default:
+		return true
+	}
+}
+
+// Duration returns the length of the interval, or 0 if it is empty.
+func (i Interval) Duration() time.Duration {
+	if i.IsEmpty() {
+		return 0
+	}
+	return i.End.Sub(i.Start)
+}
+
+// Contains returns true if t lies within the interval, respecting its bounds.
+func (i Interval) Contains(t time.Time) bool {
+	afterStart := t.After(i.Start) || (t.Equal(i.Start) && i.StartClosed())
+	beforeEnd := t.Before(i.End) || (t.Equal(i.End) && i.EndClosed())
+	return afterStart && beforeEnd
+}
+
+// ContainsInterval returns true if every instant of other lies within the interval. Empty intervals are contained
+// in any interval.
+func (i Interval) ContainsInterval(other Interval) bool {
+	if other.IsEmpty() {
+		return true
+	}
+	return compareStarts(i, other) <= 0 && compareEnds(other, i) <= 0
+}
+
+// Overlaps returns true if the intervals share at least one instant.
+func (i Interval) Overlaps(other Interval) bool {
+	_, ok := i.Intersect(other)
+	return ok
+}
+
+// Intersect returns the instants shared by both intervals, or false if there are none.
+func (i Interval) Intersect(other Interval) (Interval, bool) {
+	start, end := i, i
+	if compareStarts(other, i) > 0 {
+		start = other
+	}
+	if compareEnds(other, i) < 0 {
+		end = other
+	}
+
+	result := Interval{Start: start.Start, End: end.End, Bounds: boundsOf(start.StartClosed(), end.EndClosed())}
+	if result.IsEmpty() {
+		return Interval{}, false
+	}
+	return result, true
+}
+
+// Union returns the smallest interval covering both intervals if they overlap or touch, such as [a, b) and [b, c).
+// It returns false if a gap would remain between them.
+func (i Interval) Union(other Interval) (Interval, bool) {
+	if i.IsEmpty() {
+		return other, !other.IsEmpty()
+	}
+	if other.IsEmpty() {
+		return i, true
+	}
+
+	first, second := i, other
+	if compareStarts(other, i) < 0 {
+		first, second = other, i
+	}
+	touches := first.End.After(second.Start) ||
+		(first.End.Equal(second.Start) && (first.EndClosed() || second.StartClosed()))
+	if !touches {
+		return Interval{}, false
+	}
+
+	end := first
+	if compareEnds(second, first) > 0 {
+		end = second
+	}
+	return Interval{Start: first.Start, End: end.End, Bounds: boundsOf(first.StartClosed(), end.EndClosed())}, true
+}
+
+// Subtract returns the parts of the interval not covered by other: none, one or two intervals.
+func (i Interval) Subtract(other Interval) []Interval {
+	if i.IsEmpty() {
+		return nil
+	}
+	if !i.Overlaps(other) {
+		return []Interval{i}
+	}
+
+	var result []Interval
+	if compareStarts(i, other) < 0 {
+		left := Interval{Start: i.Start, End: other.Start, Bounds: boundsOf(i.StartClosed(), !other.StartClosed())}
+		if !left.IsEmpty() {
+			result = append(result, left)
+		}
+	}
+	if compareEnds(other, i) < 0 {
+		right := Interval{Start: other.End, End: i.End, Bounds: boundsOf(!other.EndClosed(), i.EndClosed())}
+		if !right.IsEmpty() {
+			result = append(result, right)
+		}
+	}
+	return result
+}
+
+// Split divides the interval at the calendar unit boundaries of the start's location, for example into days.
+// Inner pieces are half-open; the first and last pieces keep the interval's own start and end bounds.
+func (i Interval) Split(unit Unit) []Interval {
+	if i.IsEmpty() {
+		return nil
+	}
+	if _, ok := unitNames[unit]; !ok {
+		return []Interval{i}
+	}
+
+	var result []Interval
+	start, startClosed := i.Start, i.StartClosed()
+	for {
+		boundary := CeilTo(start, unit).Add(time.Nanosecond)
+		if !boundary.Before(i.End) {
+			break
+		}
+		result = append(result, Interval{Start: start, End: boundary, Bounds: boundsOf(startClosed, false)})
+		start, startClosed = boundary, true
+	}
+
+	last := Interval{Start: start, End: i.End, Bounds: boundsOf(startClosed, i.EndClosed())}
+	if !last.IsEmpty() {
+		result = append(result, last)
+	}
+	return result
+}
+
+// compareStarts orders intervals by their first instant; a closed start comes before an open one at the same time.
+func compareStarts(a, b Interval) int {
+	if result := a.Start.Compare(b.Start); result != 0 {
+		return result
+	}
+	switch {
+	case a.StartClosed() == b.StartClosed():
+		return 0
+	case a.StartClosed():
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareEnds orders intervals by their last instant; an open end comes before a closed one at the same time.
+func compareEnds(a, b Interval) int {
+	if result := a.End.Compare(b.End); result != 0 {
+		return result
+	}
+	switch {
+	case a.EndClosed() == b.EndClosed():
+		return 0
+	case a.EndClosed():
+		return 1
+	default:
+		return -1
+	}
+}
+
+// IntervalSet is a set of instants stored as sorted, disjoint intervals. Overlapping or touching intervals are merged.
+// The zero value is an empty set.
+type IntervalSet struct {
+	intervals []Interval
+}
+
+// NewIntervalSet creates a set from the given intervals, merging those that overlap or touch.
+func NewIntervalSet(intervals ...Interval) *IntervalSet {
+	set := &IntervalSet{}
+	set.Add(intervals...)
+	return set
+}
+
+// Add adds intervals to the set, merging those that overlap or touch.
+func (s *IntervalSet) Add(intervals ...Interval) {
+	all := make([]Interval, 0, len(s.intervals)+len(intervals))
+	all = append(all, s.intervals...)
+	for _, interval := range intervals {
+		if !interval.IsEmpty() {
+			all = append(all, interval)
+		}
+	}
+	slices.SortStableFunc(all, compareStarts)
+
+	merged := all[:0]
+	for _, interval := range all {
+		if len(merged) > 0 {
+			if union, ok := merged[len(merged)-1].Union(interval); ok {
+				merged[len(merged)-1] = union
+				continue
+			}
+		}
+		merged = append(merged, interval)
+	}
+	s.intervals = merged
+}
+
+// Intervals returns the merged intervals in ascending order.
+func (s *IntervalSet) Intervals() []Interval {
+	return slices.Clone(s.intervals)
+}
+
+// IsEmpty returns true if the set contains no interval.
+func (s *IntervalSet) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Duration returns the total length of the intervals in the set.
+func (s *IntervalSet) Duration() time.Duration {
+	var total time.Duration
+	for _, interval := range s.intervals {
+		total += interval.Duration()
+	}
+	return total
+}
+
+// Contains returns true if t lies within one of the intervals of the set.
+func (s *IntervalSet) Contains(t time.Time) bool {
+	for _, interval := range s.intervals {
+		if interval.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers returns true if every instant of the given interval lies within the set.
+func (s *IntervalSet) Covers(interval Interval) bool {
+	if interval.IsEmpty() {
+		return true
+	}
+	for _, candidate := range s.intervals {
+		if candidate.ContainsInterval(interval) {
+			return true
+		}
+	}
+	return false
+}
+
+// Gaps returns the intervals between consecutive intervals of the set.
+func (s *IntervalSet) Gaps() []Interval {
+	var gaps []Interval
+	for index := 1; index < len(s.intervals); index++ {
+		previous, next := s.intervals[index-1], s.intervals[index]
+		gaps = append(gaps, Interval{
+			Start:  previous.End,
+			End:    next.Start,
+			Bounds: boundsOf(!previous.EndClosed(), !next.StartClosed()),
+		})
+	}
+	return gaps
+}
+
+// FreeSlots returns the parts of window not covered by the set that last at least minDuration,
+// for example to find free meeting slots between busy periods.
+func (s *IntervalSet) FreeSlots(window Interval, minDuration time.Duration) []Interval {
+	if window.IsEmpty() {
+		return nil
+	}
+
+	free := []Interval{window}
+	for _, busy := range s.intervals {
+		if len(free) == 0 {
+			break
+		}
+		// the set is sorted, so only the last free piece can overlap later intervals.
+		last := free[len(free)-1]
+		free = append(free[:len(free)-1], last.Subtract(busy)...)
+	}
+
+	slots := free[:0]
+	for _, slot := range free {
+		if slot.Duration() >= minDuration {
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
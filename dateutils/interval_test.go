@@ -0,0 +1,218 @@
+package dateutils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func at(hour int) time.Time {
+	return time.Date(2024, 5, 15, hour, 0, 0, 0, time.UTC)
+}
+
+func interval(start, end int, bounds ...dateutils.IntervalBounds) dateutils.Interval {
+	return dateutils.NewInterval(at(start), at(end), bounds...)
+}
+
+func TestInterval(t *testing.T) {
+	t.Run("Contains", func(t *testing.T) {
+		testCases := []struct {
+			bounds        dateutils.IntervalBounds
+			expectedStart bool
+			expectedEnd   bool
+		}{
+			{dateutils.ClosedOpen, true, false},
+			{dateutils.Closed, true, true},
+			{dateutils.OpenClosed, false, true},
+			{dateutils.Open, false, false},
+		}
+
+		for _, testCase := range testCases {
+			i := interval(9, 17, testCase.bounds)
+			assert.Equal(t, testCase.expectedStart, i.Contains(at(9)), i.String())
+			assert.Equal(t, testCase.expectedEnd, i.Contains(at(17)), i.String())
+			assert.True(t, i.Contains(at(12)))
+			assert.False(t, i.Contains(at(18)))
+		}
+	})
+
+	t.Run("IsEmpty and Duration", func(t *testing.T) {
+		assert.False(t, interval(9, 9, dateutils.Closed).IsEmpty())
+		assert.True(t, interval(9, 9).IsEmpty())
+		assert.True(t, interval(10, 9).IsEmpty())
+		assert.Equal(t, 8*time.Hour, interval(9, 17).Duration())
+		assert.Equal(t, time.Duration(0), interval(10, 9).Duration())
+	})
+
+	t.Run("Intersect", func(t *testing.T) {
+		testCases := []struct {
+			a, b     dateutils.Interval
+			expected string
+		}{
+			{interval(9, 12), interval(11, 14), "[2024-05-15T11:00:00Z, 2024-05-15T12:00:00Z)"},
+			{interval(9, 12, dateutils.Closed), interval(12, 14), "[2024-05-15T12:00:00Z, 2024-05-15T12:00:00Z]"},
+			{interval(9, 12), interval(12, 14), ""},
+			{interval(9, 12, dateutils.Closed), interval(9, 12, dateutils.Open), "(2024-05-15T09:00:00Z, 2024-05-15T12:00:00Z)"},
+			{interval(9, 10), interval(11, 12), ""},
+		}
+
+		for _, testCase := range testCases {
+			actual, ok := testCase.a.Intersect(testCase.b)
+			assert.Equal(t, testCase.expected != "", ok)
+			assert.Equal(t, testCase.expected != "", testCase.a.Overlaps(testCase.b))
+			if ok {
+				assert.Equal(t, testCase.expected, actual.String())
+			}
+		}
+	})
+
+	t.Run("Union", func(t *testing.T) {
+		testCases := []struct {
+			a, b     dateutils.Interval
+			expected string
+		}{
+			{interval(9, 12), interval(11, 14), "[2024-05-15T09:00:00Z, 2024-05-15T14:00:00Z)"},
+			{interval(9, 12), interval(12, 14, dateutils.Closed), "[2024-05-15T09:00:00Z, 2024-05-15T14:00:00Z]"},
+			{interval(12, 14, dateutils.Open), interval(9, 12, dateutils.OpenClosed), "(2024-05-15T09:00:00Z, 2024-05-15T14:00:00Z)"},
+			{interval(9, 12), interval(12, 14, dateutils.Open), ""},
+			{interval(9, 10), interval(11, 12), ""},
+		}
+
+		for _, testCase := range testCases {
+			actual, ok := testCase.a.Union(testCase.b)
+			assert.Equal(t, testCase.expected != "", ok)
+			if ok {
+				assert.Equal(t, testCase.expected, actual.String())
+			}
+		}
+	})
+
+	t.Run("Subtract", func(t *testing.T) {
+		testCases := []struct {
+			a, b     dateutils.Interval
+			expected []string
+		}{
+			{interval(9, 17), interval(12, 13), []string{
+				"[2024-05-15T09:00:00Z, 2024-05-15T12:00:00Z)",
+				"[2024-05-15T13:00:00Z, 2024-05-15T17:00:00Z)",
+			}},
+			{interval(9, 17), interval(12, 13, dateutils.Open), []string{
+				"[2024-05-15T09:00:00Z, 2024-05-15T12:00:00Z]",
+				"[2024-05-15T13:00:00Z, 2024-05-15T17:00:00Z)",
+			}},
+			{interval(9, 17), interval(8, 10), []string{"[2024-05-15T10:00:00Z, 2024-05-15T17:00:00Z)"}},
+			{interval(9, 17), interval(18, 19), []string{"[2024-05-15T09:00:00Z, 2024-05-15T17:00:00Z)"}},
+			{interval(9, 17), interval(9, 17, dateutils.Closed), nil},
+		}
+
+		for _, testCase := range testCases {
+			var actual []string
+			for _, piece := range testCase.a.Subtract(testCase.b) {
+				actual = append(actual, piece.String())
+			}
+			assert.Equal(t, testCase.expected, actual)
+		}
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		split := dateutils.NewInterval(
+			time.Date(2024, 5, 14, 18, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 6, 0, 0, 0, time.UTC),
+			dateutils.Closed,
+		).Split(dateutils.Day)
+
+		assert.Len(t, split, 3)
+		assert.Equal(t, "[2024-05-14T18:00:00Z, 2024-05-15T00:00:00Z)", split[0].String())
+		assert.Equal(t, "[2024-05-15T00:00:00Z, 2024-05-16T00:00:00Z)", split[1].String())
+		assert.Equal(t, "[2024-05-16T00:00:00Z, 2024-05-16T06:00:00Z]", split[2].String())
+	})
+
+	t.Run("Split across DST", func(t *testing.T) {
+		newYork := mustLoadLocation(t, "America/New_York")
+		split := dateutils.NewInterval(
+			time.Date(2024, 3, 9, 0, 0, 0, 0, newYork),
+			time.Date(2024, 3, 12, 0, 0, 0, 0, newYork),
+		).Split(dateutils.Day)
+
+		var durations []time.Duration
+		for _, piece := range split {
+			durations = append(durations, piece.Duration())
+		}
+		assert.Equal(t, []time.Duration{24 * time.Hour, 23 * time.Hour, 24 * time.Hour}, durations)
+	})
+}
+
+func TestIntervalSet(t *testing.T) {
+	set := dateutils.NewIntervalSet(
+		interval(13, 14),
+		interval(9, 10),
+		interval(9, 9),
+		interval(10, 11),
+		interval(15, 17, dateutils.Closed),
+		interval(16, 16, dateutils.Closed),
+	)
+
+	t.Run("merges intervals", func(t *testing.T) {
+		var actual []string
+		for _, merged := range set.Intervals() {
+			actual = append(actual, merged.String())
+		}
+		assert.Equal(t, []string{
+			"[2024-05-15T09:00:00Z, 2024-05-15T11:00:00Z)",
+			"[2024-05-15T13:00:00Z, 2024-05-15T14:00:00Z)",
+			"[2024-05-15T15:00:00Z, 2024-05-15T17:00:00Z]",
+		}, actual)
+		assert.Equal(t, 5*time.Hour, set.Duration())
+		assert.False(t, set.IsEmpty())
+		assert.True(t, dateutils.NewIntervalSet().IsEmpty())
+	})
+
+	t.Run("Gaps", func(t *testing.T) {
+		var actual []string
+		for _, gap := range set.Gaps() {
+			actual = append(actual, gap.String())
+		}
+		assert.Equal(t, []string{
+			"[2024-05-15T11:00:00Z, 2024-05-15T13:00:00Z)",
+			"[2024-05-15T14:00:00Z, 2024-05-15T15:00:00Z)",
+		}, actual)
+	})
+
+	t.Run("FreeSlots", func(t *testing.T) {
+		var actual []string
+		for _, slot := range set.FreeSlots(interval(8, 18), 90*time.Minute) {
+			actual = append(actual, slot.String())
+		}
+		assert.Equal(t, []string{"[2024-05-15T11:00:00Z, 2024-05-15T13:00:00Z)"}, actual)
+
+		actual = nil
+		for _, slot := range set.FreeSlots(interval(8, 18), time.Hour) {
+			actual = append(actual, slot.String())
+		}
+		assert.Equal(t, []string{
+			"[2024-05-15T08:00:00Z, 2024-05-15T09:00:00Z)",
+			"[2024-05-15T11:00:00Z, 2024-05-15T13:00:00Z)",
+			"[2024-05-15T14:00:00Z, 2024-05-15T15:00:00Z)",
+			"(2024-05-15T17:00:00Z, 2024-05-15T18:00:00Z)",
+		}, actual)
+	})
+
+	t.Run("Covers and Contains", func(t *testing.T) {
+		assert.True(t, set.Covers(interval(9, 11)))
+		assert.False(t, set.Covers(interval(9, 11, dateutils.Closed)))
+		assert.True(t, set.Covers(interval(15, 17, dateutils.Closed)))
+		assert.False(t, set.Covers(interval(10, 14)))
+		assert.True(t, set.Covers(interval(12, 12)))
+		assert.True(t, set.Contains(at(17)))
+		assert.False(t, set.Contains(at(11)))
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		set := dateutils.NewIntervalSet(interval(9, 10))
+		set.Add(interval(10, 12), interval(11, 13))
+		assert.Len(t, set.Intervals(), 1)
+		assert.True(t, set.Covers(interval(9, 13)))
+	})
+}
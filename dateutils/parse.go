@@ -0,0 +1,386 @@
+package dateutils
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnrecognizedDate is returned when a value matches none of the layouts tried.
+	ErrUnrecognizedDate = errors.New("dateutils: unrecognized date")
+	// ErrAmbiguousDate is returned when a value such as "02/01/2024" reads differently day-first and month-first
+	// and no DateOrder preference is given.
+	ErrAmbiguousDate = errors.New("dateutils: ambiguous date")
+)
+
+// Pseudo layouts reported for, and accepted as, Unix epoch values.
+const (
+	LayoutUnix      = "unix"
+	LayoutUnixMilli = "unixmilli"
+	LayoutUnixMicro = "unixmicro"
+	LayoutUnixNano  = "unixnano"
+)
+
+// DateOrder - how numeric dates such as "02/01/2024" are read.
+type DateOrder int
+
+const (
+	// DateOrderAuto accepts numeric dates only when they read the same either way, or when one reading is invalid,
+	// as for "13/01/2024". It is the zero value.
+	DateOrderAuto DateOrder = iota
+	// DayFirst prefers day/month/year, falling back to month/day/year when the day-first reading is invalid.
+	DayFirst
+	// MonthFirst prefers month/day/year, falling back to day/month/year when the month-first reading is invalid.
+	MonthFirst
+)
+
+// DefaultLayouts are the unambiguous layouts tried by ParseDateAny, in order. Numeric day and month layouts such as
+// "2/1/2006" are tried after them according to the DateOrder.
+var DefaultLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700 MST",
+	time.DateTime,
+	"2006-01-02 15:04",
+	time.DateOnly,
+	"2006-1-2",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"20060102",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.RubyDate,
+	time.UnixDate,
+	time.ANSIC,
+	"Jan 2, 2006 3:04:05 PM",
+	"Jan 2, 2006 3:04:05PM",
+	"Jan 2, 2006 3:04 PM",
+	"Jan 2, 2006 3:04PM",
+	"Jan 2, 2006 15:04:05",
+	"Jan 2, 2006 15:04",
+	"Jan 2, 2006",
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006 15:04",
+	"January 2, 2006",
+	"Mon, Jan 2, 2006",
+	"Monday, January 2, 2006",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+	"2 January 2006",
+	"02-Jan-2006",
+}
+
+// numericLayouts holds the day-first and month-first readings of numeric dates.
+var numericLayouts = buildNumericLayouts()
+
+func buildNumericLayouts() [][2]string {
+	dayFirst := strings.NewReplacer("D", "2", "M", "1")
+	monthFirst := strings.NewReplacer("D", "1", "M", "2")
+
+	var layouts [][2]string
+	for _, date := range []string{"D/M/2006", "D/M/06", "D.M.2006", "D-M-2006"} {
+		for _, clock := range []string{"", " 15:04:05", " 15:04", " 3:04:05 PM", " 3:04 PM", " 3:04PM"} {
+			layouts = append(layouts, [2]string{dayFirst.Replace(date + clock), monthFirst.Replace(date + clock)})
+		}
+	}
+	return layouts
+}
+
+// DateParseOptions - multi-layout date parsing options.
+type DateParseOptions struct {
+	Layouts   []string
+	Order     DateOrder
+	Location  *time.Location
+	SkipEpoch bool
+}
+
+func parseDateParseOptions(opts ...DateParseOptions) DateParseOptions {
+	options := DateParseOptions{
+		Order:    DateOrderAuto,
+		Location: time.UTC,
+	}
+
+	for _, opt := range opts {
+		if opt.Layouts != nil {
+			options.Layouts = opt.Layouts
+		}
+		if opt.Order != DateOrderAuto {
+			options.Order = opt.Order
+		}
+		if opt.Location != nil {
+			options.Location = opt.Location
+		}
+		if opt.SkipEpoch {
+			options.SkipEpoch = true
+		}
+	}
+
+	return options
+}
+
+// ParseDateAny parses a date in any of several layouts, returning the time and the layout that matched, so that
+// the remaining values of a column can be parsed with ParseDateAs. Layouts are tried in order, then numeric
+// day/month layouts such as "02/01/2024" according to the date order, then Unix epoch values.
+// Epoch values are integers, optionally with a fraction, whose unit is detected by magnitude: seconds below 1e11,
+// milliseconds below 1e14, microseconds below 1e17 and nanoseconds above; the reported layout is LayoutUnix,
+// LayoutUnixMilli, LayoutUnixMicro or LayoutUnixNano. Compact dates such as "20240102" match the "20060102" layout
+// before being read as epoch seconds.
+// ParseDateAny also accepts an options object with the following properties:
+//
+//	DateParseOptions.Layouts: the layouts to try instead of DefaultLayouts and the numeric layouts.
+//	DateParseOptions.Order: DateOrderAuto, DayFirst or MonthFirst for numeric dates, defaults to DateOrderAuto.
+//	DateParseOptions.Location: the location of values without a time zone, defaults to UTC.
+//	DateParseOptions.SkipEpoch: whether to skip epoch detection, defaults to false.
+func ParseDateAny(value string, opts ...DateParseOptions) (time.Time, string, error) {
+	options := parseDateParseOptions(opts...)
+	value = strings.TrimSpace(value)
+
+	for _, layout := range options.layouts() {
+		if parsed, err := time.ParseInLocation(layout, value, options.Location); err == nil {
+			return parsed, layout, nil
+		}
+	}
+
+	if options.Layouts == nil {
+		for _, pair := range numericLayouts {
+			parsed, layout, err := parseNumeric(value, pair, options)
+			if err == nil || errors.Is(err, ErrAmbiguousDate) {
+				return parsed, layout, err
+			}
+		}
+	}
+
+	if !options.SkipEpoch {
+		if layout, ok := epochLayout(value); ok {
+			parsed, err := ParseDateAs(value, layout, options)
+			return parsed, layout, err
+		}
+	}
+
+	return time.Time{}, "", fmt.Errorf("%w: %q", ErrUnrecognizedDate, value)
+}
+
+// ParseDateAs parses a date in the given layout, which may also be one of the epoch pseudo layouts LayoutUnix,
+// LayoutUnixMilli, LayoutUnixMicro and LayoutUnixNano. Values without a time zone are read in
+// DateParseOptions.Location, which defaults to UTC; the other options are ignored.
+func ParseDateAs(value, layout string, opts ...DateParseOptions) (time.Time, error) {
+	options := parseDateParseOptions(opts...)
+	value = strings.TrimSpace(value)
+
+	scale, isEpoch := epochScales[layout]
+	if !isEpoch {
+		return time.ParseInLocation(layout, value, options.Location)
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || strings.Trim(fraction, "0123456789") != "" {
+		return time.Time{}, fmt.Errorf("%w: %q is not an epoch value", ErrUnrecognizedDate, value)
+	}
+
+	nanoseconds := fractionNanoseconds(fraction, scale)
+	if strings.HasPrefix(whole, "-") {
+		nanoseconds = -nanoseconds
+	}
+
+	var parsed time.Time
+	switch layout {
+	case LayoutUnix:
+		parsed = time.Unix(units, nanoseconds)
+	case LayoutUnixMilli:
+		parsed = time.UnixMilli(units).Add(time.Duration(nanoseconds))
+	case LayoutUnixMicro:
+		parsed = time.UnixMicro(units).Add(time.Duration(nanoseconds))
+	default:
+		parsed = time.Unix(0, units)
+	}
+	return parsed.In(options.Location), nil
+}
+
+// DetectDateLayout returns the first layout, in the order used by ParseDateAny, that parses every non-empty value,
+// so that a whole column is read consistently. Numeric dates are resolved across the column: a single "13/01/2024"
+// makes the column day-first.
+func DetectDateLayout(values []string, opts ...DateParseOptions) (string, error) {
+	options := parseDateParseOptions(opts...)
+
+	var trimmed []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	if len(trimmed) == 0 {
+		return "", fmt.Errorf("%w: no values", ErrUnrecognizedDate)
+	}
+
+	for _, layout := range options.layouts() {
+		if _, ok := parseAll(trimmed, layout, options); ok {
+			return layout, nil
+		}
+	}
+
+	if options.Layouts == nil {
+		for _, pair := range numericLayouts {
+			if layout, err := detectNumeric(trimmed, pair, options); layout != "" || err != nil {
+				return layout, err
+			}
+		}
+	}
+
+	if !options.SkipEpoch {
+		if layout, ok := epochLayout(trimmed[0]); ok {
+			consistent := true
+			for _, value := range trimmed[1:] {
+				if other, ok := epochLayout(value); !ok || other != layout {
+					consistent = false
+					break
+				}
+			}
+			if consistent {
+				return layout, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: no layout matches every value", ErrUnrecognizedDate)
+}
+
+// ParseDateColumn parses a column of dates with the layout returned by DetectDateLayout. Empty values are returned
+// as the zero time.
+func ParseDateColumn(values []string, opts ...DateParseOptions) ([]time.Time, string, error) {
+	layout, err := DetectDateLayout(values, opts...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := make([]time.Time, len(values))
+	for index, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		if result[index], err = ParseDateAs(value, layout, opts...); err != nil {
+			return nil, "", err
+		}
+	}
+	return result, layout, nil
+}
+
+func (o DateParseOptions) layouts() []string {
+	if o.Layouts != nil {
+		return o.Layouts
+	}
+	return DefaultLayouts
+}
+
+// parseNumeric parses a numeric date with its day-first and month-first layouts according to the date order.
+func parseNumeric(value string, pair [2]string, options DateParseOptions) (time.Time, string, error) {
+	dayFirst, dayErr := time.ParseInLocation(pair[0], value, options.Location)
+	monthFirst, monthErr := time.ParseInLocation(pair[1], value, options.Location)
+
+	switch {
+	case dayErr != nil && monthErr != nil:
+		return time.Time{}, "", dayErr
+	case dayErr != nil:
+		return monthFirst, pair[1], nil
+	case monthErr != nil:
+		return dayFirst, pair[0], nil
+	case options.Order == MonthFirst:
+		return monthFirst, pair[1], nil
+	case options.Order == DayFirst || dayFirst.Equal(monthFirst):
+		return dayFirst, pair[0], nil
+	default:
+		return time.Time{}, "", fmt.Errorf("%w: %q can be read day-first or month-first", ErrAmbiguousDate, value)
+	}
+}
+
+// detectNumeric returns the day-first or month-first layout of pair that parses every value, or "" if neither does.
+func detectNumeric(values []string, pair [2]string, options DateParseOptions) (string, error) {
+	dayFirst, dayOK := parseAll(values, pair[0], options)
+	monthFirst, monthOK := parseAll(values, pair[1], options)
+
+	switch {
+	case !dayOK && !monthOK:
+		return "", nil
+	case !dayOK:
+		return pair[1], nil
+	case !monthOK:
+		return pair[0], nil
+	case options.Order == MonthFirst:
+		return pair[1], nil
+	case options.Order == DayFirst || slices.EqualFunc(dayFirst, monthFirst, time.Time.Equal):
+		return pair[0], nil
+	default:
+		return "", fmt.Errorf("%w: the values can be read day-first or month-first", ErrAmbiguousDate)
+	}
+}
+
+func parseAll(values []string, layout string, options DateParseOptions) ([]time.Time, bool) {
+	result := make([]time.Time, 0, len(values))
+	for _, value := range values {
+		parsed, err := time.ParseInLocation(layout, value, options.Location)
+		if err != nil {
+			return nil, false
+		}
+		result = append(result, parsed)
+	}
+	return result, true
+}
+
+// epochScales holds the nanoseconds per unit of each epoch pseudo layout.
+var epochScales = map[string]int64{
+	LayoutUnix:      int64(time.Second),
+	LayoutUnixMilli: int64(time.Millisecond),
+	LayoutUnixMicro: int64(time.Microsecond),
+	LayoutUnixNano:  1,
+}
+
+// epochLayout detects the unit of an epoch value by the magnitude of its integer part.
+func epochLayout(value string) (string, bool) {
+	whole, fraction, _ := strings.Cut(value, ".")
+	digits := strings.TrimPrefix(strings.TrimPrefix(whole, "-"), "+")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return "", false
+	}
+
+	switch magnitude := len(strings.TrimLeft(digits, "0")); {
+	case magnitude <= 11:
+		return LayoutUnix, true
+	case magnitude <= 14:
+		return LayoutUnixMilli, true
+	case magnitude <= 17:
+		return LayoutUnixMicro, true
+	default:
+		return LayoutUnixNano, true
+	}
+}
+
+// fractionNanoseconds converts the decimal fraction of a unit scale nanoseconds long into nanoseconds.
+func fractionNanoseconds(fraction string, scale int64) int64 {
+	if len(fraction) > 9 {
+		fraction = fraction[:9]
+	}
+	if fraction == "" {
+		return 0
+	}
+
+	numerator, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil {
+		return 0
+	}
+	denominator := int64(1)
+	for range fraction {
+		denominator *= 10
+	}
+	return numerator * scale / denominator
+}
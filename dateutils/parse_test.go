@@ -0,0 +1,140 @@
+package dateutils_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateAny(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	testCases := []struct {
+		name           string
+		value          string
+		options        []dateutils.DateParseOptions
+		expected       time.Time
+		expectedLayout string
+	}{
+		{"RFC 3339", "2024-01-02T15:04:05Z", nil, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), time.RFC3339},
+		{"RFC 3339 with fraction", "2024-01-02T15:04:05.25+02:00", nil, time.Date(2024, 1, 2, 13, 4, 5, 250000000, time.UTC), time.RFC3339},
+		{"ISO date", "2024-01-02", nil, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), time.DateOnly},
+		{"date time", " 2024-01-02 15:04:05 ", nil, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), time.DateTime},
+		{"compact date", "20240102", nil, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "20060102"},
+		{"month name", "Jan 2, 2024 3:04PM", nil, time.Date(2024, 1, 2, 15, 4, 0, 0, time.UTC), "Jan 2, 2006 3:04PM"},
+		{"long month name", "January 2, 2024", nil, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "January 2, 2006"},
+		{"RFC 1123", "Tue, 02 Jan 2024 15:04:05 GMT", nil, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), time.RFC1123},
+		{"unambiguous day first", "13/01/2024", nil, time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC), "2/1/2006"},
+		{"unambiguous month first", "01/13/2024", nil, time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC), "1/2/2006"},
+		{"same either way", "05/05/2024", nil, time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC), "2/1/2006"},
+		{"day first preference", "02/01/2024", []dateutils.DateParseOptions{{Order: dateutils.DayFirst}}, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "2/1/2006"},
+		{"month first preference", "02/01/2024", []dateutils.DateParseOptions{{Order: dateutils.MonthFirst}}, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "1/2/2006"},
+		{"preference falls back", "13/01/2024", []dateutils.DateParseOptions{{Order: dateutils.MonthFirst}}, time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC), "2/1/2006"},
+		{"dotted with time", "24.12.2024 18:30", nil, time.Date(2024, 12, 24, 18, 30, 0, 0, time.UTC), "2.1.2006 15:04"},
+		{"two digit year", "12/25/24", nil, time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), "1/2/06"},
+		{"epoch seconds", "1704207845", nil, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), dateutils.LayoutUnix},
+		{"epoch seconds with fraction", "1704207845.5", nil, time.Date(2024, 1, 2, 15, 4, 5, 500000000, time.UTC), dateutils.LayoutUnix},
+		{"epoch milliseconds", "1704207845123", nil, time.Date(2024, 1, 2, 15, 4, 5, 123000000, time.UTC), dateutils.LayoutUnixMilli},
+		{"epoch microseconds", "1704207845123456", nil, time.Date(2024, 1, 2, 15, 4, 5, 123456000, time.UTC), dateutils.LayoutUnixMicro},
+		{"epoch nanoseconds", "1704207845123456789", nil, time.Date(2024, 1, 2, 15, 4, 5, 123456789, time.UTC), dateutils.LayoutUnixNano},
+		{"negative epoch", "-86400", nil, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), dateutils.LayoutUnix},
+		{"default location", "2024-07-04 09:00", []dateutils.DateParseOptions{{Location: newYork}}, time.Date(2024, 7, 4, 9, 0, 0, 0, newYork), "2006-01-02 15:04"},
+		{"custom layouts", "04|07|2024", []dateutils.DateParseOptions{{Layouts: []string{"02|01|2006"}}}, time.Date(2024, 7, 4, 0, 0, 0, 0, time.UTC), "02|01|2006"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			parsed, layout, err := dateutils.ParseDateAny(testCase.value, testCase.options...)
+			assert.NoError(t, err)
+			assert.True(t, testCase.expected.Equal(parsed), "expected %s, got %s", testCase.expected, parsed)
+			assert.Equal(t, testCase.expectedLayout, layout)
+		})
+	}
+
+	t.Run("location of epoch values", func(t *testing.T) {
+		parsed, _, err := dateutils.ParseDateAny("1704207845", dateutils.DateParseOptions{Location: newYork})
+		assert.NoError(t, err)
+		assert.Equal(t, newYork, parsed.Location())
+	})
+
+	errorCases := []struct {
+		value    string
+		options  []dateutils.DateParseOptions
+		expected error
+	}{
+		{"02/01/2024", nil, dateutils.ErrAmbiguousDate},
+		{"not a date", nil, dateutils.ErrUnrecognizedDate},
+		{"", nil, dateutils.ErrUnrecognizedDate},
+		{"1704207845", []dateutils.DateParseOptions{{SkipEpoch: true}}, dateutils.ErrUnrecognizedDate},
+		{"2024-01-02", []dateutils.DateParseOptions{{Layouts: []string{"02|01|2006"}, SkipEpoch: true}}, dateutils.ErrUnrecognizedDate},
+	}
+	for _, testCase := range errorCases {
+		_, _, err := dateutils.ParseDateAny(testCase.value, testCase.options...)
+		assert.True(t, errors.Is(err, testCase.expected), "%q: %v", testCase.value, err)
+	}
+}
+
+func TestParseDateAs(t *testing.T) {
+	parsed, err := dateutils.ParseDateAs("1704207845", dateutils.LayoutUnixMilli)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1970, 1, 20, 17, 23, 27, 845000000, time.UTC), parsed)
+
+	parsed, err = dateutils.ParseDateAs("02/01/2024", "1/2/2006")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), parsed)
+
+	_, err = dateutils.ParseDateAs("abc", dateutils.LayoutUnix)
+	assert.True(t, errors.Is(err, dateutils.ErrUnrecognizedDate))
+}
+
+func TestDetectDateLayout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []string
+		options  []dateutils.DateParseOptions
+		expected string
+		err      error
+	}{
+		{"ISO dates", []string{"2024-01-02", "", "2024-02-03"}, nil, time.DateOnly, nil},
+		{"column resolves day first", []string{"02/01/2024", "13/01/2024"}, nil, "2/1/2006", nil},
+		{"column resolves month first", []string{"02/01/2024", "01/13/2024"}, nil, "1/2/2006", nil},
+		{"ambiguous column", []string{"02/01/2024", "03/01/2024"}, nil, "", dateutils.ErrAmbiguousDate},
+		{"ambiguous column with preference", []string{"02/01/2024", "03/01/2024"}, []dateutils.DateParseOptions{{Order: dateutils.MonthFirst}}, "1/2/2006", nil},
+		{"epoch milliseconds", []string{"1704207845123", "1704207846000"}, nil, dateutils.LayoutUnixMilli, nil},
+		{"mixed epoch units", []string{"1704207845", "1704207846000"}, nil, "", dateutils.ErrUnrecognizedDate},
+		{"mixed layouts", []string{"2024-01-02", "Jan 2, 2024"}, nil, "", dateutils.ErrUnrecognizedDate},
+		{"no values", []string{"", " "}, nil, "", dateutils.ErrUnrecognizedDate},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			layout, err := dateutils.DetectDateLayout(testCase.values, testCase.options...)
+			if testCase.err != nil {
+				assert.True(t, errors.Is(err, testCase.err), "%v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.expected, layout)
+		})
+	}
+}
+
+func TestParseDateColumn(t *testing.T) {
+	parsed, layout, err := dateutils.ParseDateColumn([]string{"02/01/2024", "", "13/01/2024"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2/1/2006", layout)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		{},
+		time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC),
+	}, parsed)
+
+	_, _, err = dateutils.ParseDateColumn([]string{"x"})
+	assert.Error(t, err)
+}
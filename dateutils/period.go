@@ -0,0 +1,300 @@
+package dateutils
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPeriod is returned when an ISO 8601 duration cannot be parsed.
+var ErrInvalidPeriod = errors.New("dateutils: invalid ISO 8601 duration")
+
+// average calendar lengths in the Gregorian calendar, used by ApproxDuration.
+const (
+	averageYear  = 31556952 * time.Second
+	averageMonth = averageYear / 12
+)
+
+// Period is an ISO 8601 duration such as "P1Y2M10DT2H30M". The calendar components (years, months, weeks and days)
+// are kept separately from the exact time components, because their length depends on the date they are added to:
+// a month can have 28 to 31 days, and a day can be 23 to 25 hours long across DST changes.
+// Components may be negative. The zero value is an empty period.
+type Period struct {
+	Years       int
+	Months      int
+	Weeks       int
+	Days        int
+	Hours       int
+	Minutes     int
+	Seconds     int
+	Nanoseconds int
+}
+
+// ParsePeriod parses an ISO 8601 duration, such as "P1Y2M10DT2H30M", "P3W" or "PT0.5S". A leading sign negates
+// the whole period ("-P1D") and components may carry their own sign ("P1M-1D"). Only seconds may have a fraction,
+// with a dot or a comma and up to nine digits.
+func ParsePeriod(value string) (Period, error) {
+	text := strings.ToUpper(strings.TrimSpace(value))
+
+	text, negative := strings.CutPrefix(text, "-")
+	if !negative {
+		text = strings.TrimPrefix(text, "+")
+	}
+
+	rest, ok := strings.CutPrefix(text, "P")
+	if !ok || rest == "" {
+		return Period{}, fmt.Errorf("%w: %q", ErrInvalidPeriod, value)
+	}
+	datePart, timePart, hasTime := strings.Cut(rest, "T")
+	if hasTime && timePart == "" {
+		return Period{}, fmt.Errorf("%w: %q has no time components after T", ErrInvalidPeriod, value)
+	}
+
+	var period Period
+	dateFields := []periodField{{'Y', &period.Years}, {'M', &period.Months}, {'W', &period.Weeks}, {'D', &period.Days}}
+	timeFields := []periodField{{'H', &period.Hours}, {'M', &period.Minutes}, {'S', &period.Seconds}}
+
+	if err := parsePeriodFields(datePart, dateFields, nil); err != nil {
+		return Period{}, fmt.Errorf("%w: %q: %w", ErrInvalidPeriod, value, err)
+	}
+	if err := parsePeriodFields(timePart, timeFields, &period.Nanoseconds); err != nil {
+		return Period{}, fmt.Errorf("%w: %q: %w", ErrInvalidPeriod, value, err)
+	}
+
+	if negative {
+		period = period.Negate()
+	}
+	return period, nil
+}
+
+// MustParsePeriod parses an ISO 8601 duration like ParsePeriod.
+// Panics if parsing fails.
+func MustParsePeriod(value string) Period {
+	period, err := ParsePeriod(value)
+	if err != nil {
+		panic(err)
+	}
+	return period
+}
+
+type periodField struct {
+	designator byte
+	target     *int
+}
+
+// parsePeriodFields parses components such as "1Y2M" in the order of fields. A fraction is accepted on the last
+// field only, and only when nanoseconds is set.
+func parsePeriodFields(text string, fields []periodField, nanoseconds *int) error {
+	next := 0
+	for text != "" {
+		end := strings.IndexFunc(text, func(r rune) bool { return r >= 'A' && r <= 'Z' })
+		if end <= 0 {
+			return fmt.Errorf("missing number or designator in %q", text)
+		}
+		number, designator := text[:end], text[end]
+		text = text[end+1:]
+
+		index := next
+		for index < len(fields) && fields[index].designator != designator {
+			index++
+		}
+		if index == len(fields) {
+			return fmt.Errorf("unexpected designator %q", designator)
+		}
+		next = index + 1
+
+		whole, fraction, hasFraction := strings.Cut(strings.ReplaceAll(number, ",", "."), ".")
+		if hasFraction && (nanoseconds == nil || index != len(fields)-1 || fraction == "" || len(fraction) > 9 ||
+			strings.Trim(fraction, "0123456789") != "") {
+			return fmt.Errorf("invalid fraction in %q", number)
+		}
+
+		parsed, err := strconv.Atoi(whole)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", number)
+		}
+		*fields[index].target = parsed
+
+		if hasFraction {
+			nanos, err := strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction)))
+			if err != nil {
+				return fmt.Errorf("invalid fraction in %q", number)
+			}
+			if strings.HasPrefix(whole, "-") {
+				nanos = -nanos
+			}
+			*nanoseconds = nanos
+		}
+	}
+	return nil
+}
+
+// PeriodFromDuration returns a period with the exact time components of d, normalized to hours, minutes, seconds
+// and nanoseconds.
+func PeriodFromDuration(d time.Duration) Period {
+	return Period{Nanoseconds: int(d)}.Normalized()
+}
+
+// String formats the period as an ISO 8601 duration, such as "P1Y2M10DT2H30M". A period whose components are all
+// zero or negative is formatted with a leading minus sign ("-P1D"); the zero period is "P0D".
+func (p Period) String() string {
+	if p.IsZero() {
+		return "P0D"
+	}
+
+	sign := ""
+	if p.allNonPositive() {
+		sign, p = "-", p.Negate()
+	}
+
+	var builder strings.Builder
+	builder.WriteString(sign + "P")
+	for _, component := range []struct {
+		value      int
+		designator string
+	}{{p.Years, "Y"}, {p.Months, "M"}, {p.Weeks, "W"}, {p.Days, "D"}} {
+		if component.value != 0 {
+			builder.WriteString(strconv.Itoa(component.value) + component.designator)
+		}
+	}
+
+	seconds := time.Duration(p.Seconds)*time.Second + time.Duration(p.Nanoseconds)
+	if p.Hours == 0 && p.Minutes == 0 && seconds == 0 {
+		return builder.String()
+	}
+
+	builder.WriteString("T")
+	if p.Hours != 0 {
+		builder.WriteString(strconv.Itoa(p.Hours) + "H")
+	}
+	if p.Minutes != 0 {
+		builder.WriteString(strconv.Itoa(p.Minutes) + "M")
+	}
+	if seconds != 0 {
+		builder.WriteString(formatPeriodSeconds(seconds) + "S")
+	}
+	return builder.String()
+}
+
+// formatPeriodSeconds formats seconds with up to nine fractional digits and no trailing zeros, e.g. "1.5".
+func formatPeriodSeconds(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	text := sign + strconv.FormatInt(int64(d/time.Second), 10)
+	if fraction := d % time.Second; fraction != 0 {
+		text += "." + strings.TrimRight(fmt.Sprintf("%09d", int64(fraction)), "0")
+	}
+	return text
+}
+
+// MarshalText implements encoding.TextMarshaler, so periods are stored in configuration and JSON as ISO 8601 strings.
+func (p Period) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParsePeriod.
+func (p *Period) UnmarshalText(text []byte) error {
+	period, err := ParsePeriod(string(text))
+	if err != nil {
+		return err
+	}
+	*p = period
+	return nil
+}
+
+// IsZero returns true if every component of the period is zero.
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// Negate returns the period with every component negated.
+func (p Period) Negate() Period {
+	return Period{
+		Years: -p.Years, Months: -p.Months, Weeks: -p.Weeks, Days: -p.Days,
+		Hours: -p.Hours, Minutes: -p.Minutes, Seconds: -p.Seconds, Nanoseconds: -p.Nanoseconds,
+	}
+}
+
+// Plus returns the component-wise sum of the periods.
+func (p Period) Plus(other Period) Period {
+	return Period{
+		Years: p.Years + other.Years, Months: p.Months + other.Months,
+		Weeks: p.Weeks + other.Weeks, Days: p.Days + other.Days,
+		Hours: p.Hours + other.Hours, Minutes: p.Minutes + other.Minutes,
+		Seconds: p.Seconds + other.Seconds, Nanoseconds: p.Nanoseconds + other.Nanoseconds,
+	}
+}
+
+// TimeDuration returns the exact length of the time components, ignoring years, months, weeks and days.
+func (p Period) TimeDuration() time.Duration {
+	return time.Duration(p.Hours)*time.Hour + time.Duration(p.Minutes)*time.Minute +
+		time.Duration(p.Seconds)*time.Second + time.Duration(p.Nanoseconds)
+}
+
+// ApproxDuration returns the approximate length of the period, counting a year as 365.2425 days, a month as a
+// twelfth of that and a day as 24 hours.
+func (p Period) ApproxDuration() time.Duration {
+	return time.Duration(p.Years)*averageYear + time.Duration(p.Months)*averageMonth +
+		time.Duration(p.Weeks*7+p.Days)*24*time.Hour + p.TimeDuration()
+}
+
+// Normalized returns an equivalent period in canonical form: months are carried into years, weeks are folded into
+// days and the time components are carried into hours, minutes and seconds. Days are not converted to months or
+// hours, since their length depends on the date. For example, "P1Y14M2WT90M" becomes "P2Y2M14DT1H30M".
+func (p Period) Normalized() Period {
+	months := p.Years*12 + p.Months
+	exact := p.TimeDuration()
+
+	return Period{
+		Years:       months / 12,
+		Months:      months % 12,
+		Days:        p.Weeks*7 + p.Days,
+		Hours:       int(exact / time.Hour),
+		Minutes:     int(exact % time.Hour / time.Minute),
+		Seconds:     int(exact % time.Minute / time.Second),
+		Nanoseconds: int(exact % time.Second),
+	}
+}
+
+// Equal returns true if the periods have the same normalized components, so "PT90M" equals "PT1H30M" and "P1W"
+// equals "P7D", but "P1D" does not equal "PT24H".
+func (p Period) Equal(other Period) bool {
+	return p.Normalized() == other.Normalized()
+}
+
+// Compare compares the approximate lengths of the periods (see ApproxDuration), returning -1, 0 or +1.
+// Use CompareAt to compare calendar components exactly.
+func (p Period) Compare(other Period) int {
+	return cmp.Compare(p.ApproxDuration(), other.ApproxDuration())
+}
+
+// CompareAt compares the periods by adding both to t, returning -1, 0 or +1. "P1M" is shorter than "P30D" when
+// added to February 1st, and longer when added to January 1st.
+func (p Period) CompareAt(other Period, t time.Time) int {
+	return p.AddTo(t).Compare(other.AddTo(t))
+}
+
+// AddTo returns t plus the period. Years and months are added first, clamping to the end of the month, so January
+// 31st plus "P1M" is the last day of February. Weeks and days are added next and keep the wall clock time across
+// DST changes, then the time components are added as elapsed time.
+func (p Period) AddTo(t time.Time) time.Time {
+	t = addMonths(t, p.Years*12+p.Months, MonthEndClamp)
+	t = t.AddDate(0, 0, p.Weeks*7+p.Days)
+	return t.Add(p.TimeDuration())
+}
+
+// SubtractFrom returns t minus the period, applying the negated components in the same order as AddTo.
+func (p Period) SubtractFrom(t time.Time) time.Time {
+	return p.Negate().AddTo(t)
+}
+
+func (p Period) allNonPositive() bool {
+	return p.Years <= 0 && p.Months <= 0 && p.Weeks <= 0 && p.Days <= 0 &&
+		p.Hours <= 0 && p.Minutes <= 0 && p.Seconds <= 0 && p.Nanoseconds <= 0
+}
@@ -0,0 +1,129 @@
+package dateutils_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeriod(t *testing.T) {
+	testCases := []struct {
+		value     string
+		expected  dateutils.Period
+		formatted string
+	}{
+		{"P1Y2M10DT2H30M", dateutils.Period{Years: 1, Months: 2, Days: 10, Hours: 2, Minutes: 30}, "P1Y2M10DT2H30M"},
+		{"P3W", dateutils.Period{Weeks: 3}, "P3W"},
+		{"PT36H", dateutils.Period{Hours: 36}, "PT36H"},
+		{"PT0.5S", dateutils.Period{Nanoseconds: 500000000}, "PT0.5S"},
+		{"PT1,25S", dateutils.Period{Seconds: 1, Nanoseconds: 250000000}, "PT1.25S"},
+		{"P1M", dateutils.Period{Months: 1}, "P1M"},
+		{"PT1M", dateutils.Period{Minutes: 1}, "PT1M"},
+		{"p1dt12h", dateutils.Period{Days: 1, Hours: 12}, "P1DT12H"},
+		{"-P1D", dateutils.Period{Days: -1}, "-P1D"},
+		{"-PT1.5S", dateutils.Period{Seconds: -1, Nanoseconds: -500000000}, "-PT1.5S"},
+		{"+P2D", dateutils.Period{Days: 2}, "P2D"},
+		{"P1M-1D", dateutils.Period{Months: 1, Days: -1}, "P1M-1D"},
+		{"P0D", dateutils.Period{}, "P0D"},
+		{"PT0S", dateutils.Period{}, "P0D"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			period, err := dateutils.ParsePeriod(testCase.value)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, period)
+			assert.Equal(t, testCase.formatted, period.String())
+		})
+	}
+
+	for _, value := range []string{"", "P", "1D", "PT", "P1DT", "P1H", "PT1D", "P1D2Y", "P1.5D", "PT1.5M", "PT1.S", "P1Y1Y", "PxD", "--P1D"} {
+		_, err := dateutils.ParsePeriod(value)
+		assert.True(t, errors.Is(err, dateutils.ErrInvalidPeriod), value)
+	}
+
+	assert.Panics(t, func() { dateutils.MustParsePeriod("1 day") })
+}
+
+func TestPeriodFromDuration(t *testing.T) {
+	period := dateutils.PeriodFromDuration(26*time.Hour + 3*time.Minute + 4500*time.Millisecond)
+	assert.Equal(t, dateutils.Period{Hours: 26, Minutes: 3, Seconds: 4, Nanoseconds: 500000000}, period)
+	assert.Equal(t, "PT26H3M4.5S", period.String())
+	assert.Equal(t, 26*time.Hour+3*time.Minute+4500*time.Millisecond, period.TimeDuration())
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	testCases := []struct {
+		name     string
+		start    time.Time
+		period   string
+		expected time.Time
+	}{
+		{"month end clamps", time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC), "P1M", time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC)},
+		{"year from leap day", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "P1Y", time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"months before days", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), "P1M1D", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"full period", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "P1Y2M10DT2H30M", time.Date(2025, 3, 11, 2, 30, 0, 0, time.UTC)},
+		{"weeks", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "P3W", time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)},
+		{"negative", time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), "-P1M", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"day keeps wall clock across DST", time.Date(2024, 3, 9, 12, 0, 0, 0, newYork), "P1D", time.Date(2024, 3, 10, 12, 0, 0, 0, newYork)},
+		{"hours are elapsed time across DST", time.Date(2024, 3, 9, 12, 0, 0, 0, newYork), "PT24H", time.Date(2024, 3, 10, 13, 0, 0, 0, newYork)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			period := dateutils.MustParsePeriod(testCase.period)
+			result := period.AddTo(testCase.start)
+			assert.True(t, testCase.expected.Equal(result), "expected %s, got %s", testCase.expected, result)
+		})
+	}
+
+	start := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), dateutils.MustParsePeriod("P1M").SubtractFrom(start))
+}
+
+func TestPeriodNormalizeAndCompare(t *testing.T) {
+	assert.Equal(t, "P2Y2M14DT1H30M", dateutils.MustParsePeriod("P1Y14M2WT90M").Normalized().String())
+	assert.Equal(t, "P11M", dateutils.MustParsePeriod("P1Y-1M").Normalized().String())
+
+	assert.True(t, dateutils.MustParsePeriod("PT90M").Equal(dateutils.MustParsePeriod("PT1H30M")))
+	assert.True(t, dateutils.MustParsePeriod("P1W").Equal(dateutils.MustParsePeriod("P7D")))
+	assert.True(t, dateutils.MustParsePeriod("P12M").Equal(dateutils.MustParsePeriod("P1Y")))
+	assert.False(t, dateutils.MustParsePeriod("P1D").Equal(dateutils.MustParsePeriod("PT24H")))
+
+	assert.Equal(t, -1, dateutils.MustParsePeriod("P29D").Compare(dateutils.MustParsePeriod("P1M")))
+	assert.Equal(t, 1, dateutils.MustParsePeriod("P31D").Compare(dateutils.MustParsePeriod("P1M")))
+	assert.Equal(t, 0, dateutils.MustParsePeriod("P1D").Compare(dateutils.MustParsePeriod("PT24H")))
+
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, -1, dateutils.MustParsePeriod("P1M").CompareAt(dateutils.MustParsePeriod("P30D"), february))
+	assert.Equal(t, 1, dateutils.MustParsePeriod("P1M").CompareAt(dateutils.MustParsePeriod("P30D"), january))
+
+	assert.Equal(t, dateutils.Period{Months: 1, Days: 2}, dateutils.Period{Months: 1}.Plus(dateutils.Period{Days: 2}))
+	assert.True(t, dateutils.Period{}.IsZero())
+}
+
+func TestPeriodText(t *testing.T) {
+	type config struct {
+		Retention dateutils.Period `json:"retention"`
+	}
+
+	var decoded config
+	assert.NoError(t, json.Unmarshal([]byte(`{"retention": "P30DT12H"}`), &decoded))
+	assert.Equal(t, dateutils.Period{Days: 30, Hours: 12}, decoded.Retention)
+
+	encoded, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"retention": "P30DT12H"}`, string(encoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"retention": "30 days"}`), &decoded))
+}
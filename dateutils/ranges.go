@@ -0,0 +1,142 @@
+package dateutils
+
+import (
+	"iter"
+	"time"
+)
+
+// MonthEnd - how month-based steps treat days that do not exist in the target month.
+type MonthEnd int
+
+const (
+	// MonthEndClamp moves to the last day of the target month: January 31st plus one month is February 29th (or 28th).
+	MonthEndClamp MonthEnd = iota
+	// MonthEndOverflow carries excess days into the next month, like time.AddDate: January 31st plus one month is
+	// March 2nd (or 3rd).
+	MonthEndOverflow
+)
+
+// RangeOptions - date range iteration options.
+type RangeOptions struct {
+	Step      int
+	MonthEnd  MonthEnd
+	Inclusive bool
+}
+
+func parseRangeOptions(opts ...RangeOptions) RangeOptions {
+	options := RangeOptions{
+		Step:     1,
+		MonthEnd: MonthEndClamp,
+	}
+
+	for _, opt := range opts {
+		if opt.Step != 0 {
+			options.Step = opt.Step
+		}
+		if opt.MonthEnd != MonthEndClamp {
+			options.MonthEnd = opt.MonthEnd
+		}
+		if opt.Inclusive {
+			options.Inclusive = true
+		}
+	}
+
+	return options
+}
+
+// Range returns an iterator over the times from start to end, stepping by the given calendar unit.
+// Every time is computed from start rather than from the previous one, so month-end handling does not drift:
+// with clamping, monthly steps from January 31st yield February 29th, March 31st, April 30th and so on.
+// Day-based and larger steps keep the wall clock time of start in its location across DST changes; second, minute
+// and hour steps advance by elapsed time.
+// Range also accepts an options object with the following properties:
+//
+//	RangeOptions.Step: the number of units per step, defaults to 1. Negative steps iterate backwards towards end.
+//	RangeOptions.MonthEnd: MonthEndClamp or MonthEndOverflow for month, quarter and year steps, defaults to MonthEndClamp.
+//	RangeOptions.Inclusive: whether end itself is yielded when it is reached, defaults to false.
+func Range(start, end time.Time, unit Unit, opts ...RangeOptions) iter.Seq[time.Time] {
+	options := parseRangeOptions(opts...)
+
+	return func(yield func(time.Time) bool) {
+		if _, ok := unitNames[unit]; !ok {
+			return
+		}
+
+		for index := 0; ; index++ {
+			current := addUnits(start, unit, index*options.Step, options.MonthEnd)
+
+			comparison := current.Compare(end) * sign(options.Step)
+			if comparison > 0 || (comparison == 0 && !options.Inclusive) {
+				return
+			}
+			if !yield(current) {
+				return
+			}
+		}
+	}
+}
+
+// EachDay returns an iterator over the days from start to end, excluding end. See Range.
+func EachDay(start, end time.Time, opts ...RangeOptions) iter.Seq[time.Time] {
+	return Range(start, end, Day, opts...)
+}
+
+// EachWeek returns an iterator over the weeks from start to end, excluding end. See Range.
+func EachWeek(start, end time.Time, opts ...RangeOptions) iter.Seq[time.Time] {
+	return Range(start, end, Week, opts...)
+}
+
+// EachMonth returns an iterator over the months from start to end, excluding end. See Range.
+func EachMonth(start, end time.Time, opts ...RangeOptions) iter.Seq[time.Time] {
+	return Range(start, end, Month, opts...)
+}
+
+// addUnits adds n calendar units to t. Units of a day or longer keep the wall clock time in t's location.
+func addUnits(t time.Time, unit Unit, n int, monthEnd MonthEnd) time.Time {
+	switch unit {
+	case Second:
+		return t.Add(time.Duration(n) * time.Second)
+	case Minute:
+		return t.Add(time.Duration(n) * time.Minute)
+	case Hour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case Day:
+		return t.AddDate(0, 0, n)
+	case Week:
+		return t.AddDate(0, 0, 7*n)
+	case Month:
+		return addMonths(t, n, monthEnd)
+	case Quarter:
+		return addMonths(t, 3*n, monthEnd)
+	case Year:
+		return addMonths(t, 12*n, monthEnd)
+	default:
+		return t
+	}
+}
+
+func addMonths(t time.Time, months int, monthEnd MonthEnd) time.Time {
+	if monthEnd == MonthEndOverflow {
+		return t.AddDate(0, months, 0)
+	}
+
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+
+	return time.Date(
+		firstOfTarget.Year(), firstOfTarget.Month(), min(day, DaysInMonth(firstOfTarget)),
+		hour, minute, second, t.Nanosecond(), t.Location(),
+	)
+}
+
+func sign(value int) int {
+	switch {
+	case value < 0:
+		return -1
+	case value > 0:
+		return 1
+	default:
+		return 0
+	}
+}
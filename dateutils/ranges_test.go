@@ -0,0 +1,126 @@
+package dateutils_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func formatAll(times []time.Time, layout string) []string {
+	result := make([]string, 0, len(times))
+	for _, t := range times {
+		result = append(result, t.Format(layout))
+	}
+	return result
+}
+
+func TestRange(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	testCases := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		unit     dateutils.Unit
+		options  dateutils.RangeOptions
+		layout   string
+		expected []string
+	}{
+		{
+			"days exclusive",
+			time.Date(2024, 5, 30, 8, 0, 0, 0, time.UTC), time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC),
+			dateutils.Day, dateutils.RangeOptions{}, time.DateOnly,
+			[]string{"2024-05-30", "2024-05-31", "2024-06-01"},
+		},
+		{
+			"days inclusive",
+			time.Date(2024, 5, 30, 8, 0, 0, 0, time.UTC), time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC),
+			dateutils.Day, dateutils.RangeOptions{Inclusive: true}, time.DateOnly,
+			[]string{"2024-05-30", "2024-05-31", "2024-06-01", "2024-06-02"},
+		},
+		{
+			"months clamped",
+			time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			dateutils.Month, dateutils.RangeOptions{}, time.DateOnly,
+			[]string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31"},
+		},
+		{
+			"months overflow",
+			time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+			dateutils.Month, dateutils.RangeOptions{MonthEnd: dateutils.MonthEndOverflow}, time.DateOnly,
+			[]string{"2023-01-31", "2023-03-03", "2023-03-31"},
+		},
+		{
+			"leap years clamped",
+			time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			dateutils.Year, dateutils.RangeOptions{}, time.DateOnly,
+			[]string{"2020-02-29", "2021-02-28", "2022-02-28", "2023-02-28", "2024-02-29"},
+		},
+		{
+			"quarters",
+			time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			dateutils.Quarter, dateutils.RangeOptions{}, time.DateOnly,
+			[]string{"2024-01-15", "2024-04-15", "2024-07-15", "2024-10-15"},
+		},
+		{
+			"every other week",
+			time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			dateutils.Week, dateutils.RangeOptions{Step: 2}, time.DateOnly,
+			[]string{"2024-05-01", "2024-05-15", "2024-05-29"},
+		},
+		{
+			"backwards",
+			time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			dateutils.Month, dateutils.RangeOptions{Step: -1, Inclusive: true}, time.DateOnly,
+			[]string{"2024-03-31", "2024-02-29", "2024-01-31", "2023-12-31"},
+		},
+		{
+			"days across DST keep wall clock",
+			time.Date(2024, 3, 9, 9, 0, 0, 0, newYork), time.Date(2024, 3, 12, 0, 0, 0, 0, newYork),
+			dateutils.Day, dateutils.RangeOptions{}, time.RFC3339,
+			[]string{"2024-03-09T09:00:00-05:00", "2024-03-10T09:00:00-04:00", "2024-03-11T09:00:00-04:00"},
+		},
+		{
+			"hours across DST use elapsed time",
+			time.Date(2024, 3, 10, 0, 0, 0, 0, newYork), time.Date(2024, 3, 10, 4, 0, 0, 0, newYork),
+			dateutils.Hour, dateutils.RangeOptions{}, time.RFC3339,
+			[]string{"2024-03-10T00:00:00-05:00", "2024-03-10T01:00:00-05:00", "2024-03-10T03:00:00-04:00"},
+		},
+		{
+			"empty when end is before start",
+			time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			dateutils.Day, dateutils.RangeOptions{}, time.DateOnly,
+			[]string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual := slices.Collect(dateutils.Range(testCase.start, testCase.end, testCase.unit, testCase.options))
+			assert.Equal(t, testCase.expected, formatAll(actual, testCase.layout))
+		})
+	}
+
+	t.Run("stops early", func(t *testing.T) {
+		count := 0
+		for range dateutils.EachDay(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("helpers", func(t *testing.T) {
+		start := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		assert.Len(t, slices.Collect(dateutils.EachDay(start, end)), 30)
+		assert.Len(t, slices.Collect(dateutils.EachWeek(start, end)), 5)
+		assert.Len(t, slices.Collect(dateutils.EachMonth(start, end)), 2)
+		assert.Empty(t, slices.Collect(dateutils.Range(start, end, dateutils.Unit(0))))
+	})
+}
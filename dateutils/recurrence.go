@@ -0,0 +1,291 @@
+package dateutils
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	icalUTCLayout   = "20060102T150405Z"
+	icalLocalLayout = "20060102T150405"
+	icalDateLayout  = "20060102"
+)
+
+// Recurrence is an RFC 5545 recurrence set: a DTSTART, recurrence rules, and extra (RDATE) and excluded (EXDATE)
+// occurrences. DTSTART is always the first occurrence of the set unless it is excluded.
+type Recurrence struct {
+	Start   time.Time
+	Rules   []*RRule
+	RDates  []time.Time
+	ExDates []time.Time
+}
+
+// ParseRecurrence parses the DTSTART, RRULE, RDATE and EXDATE properties of an event, one per line:
+//
+//	DTSTART;TZID=America/New_York:19970902T090000
+//	RRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13
+//	EXDATE;TZID=America/New_York:19970902T090000
+//
+// Times without a UTC designator or TZID use the location of DTSTART, or UTC if DTSTART has neither.
+func ParseRecurrence(text string) (*Recurrence, error) {
+	recurrence := &Recurrence{}
+	var rules, rdates, exdates []icalProperty
+
+	for _, line := range unfoldICalLines(text) {
+		property, err := parseICalProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch property.name {
+		case "DTSTART":
+			if !recurrence.Start.IsZero() {
+				return nil, fmt.Errorf("%w: repeated DTSTART", ErrInvalidRRule)
+			}
+			times, err := property.times(time.UTC)
+			if err != nil || len(times) != 1 {
+				return nil, fmt.Errorf("%w: invalid DTSTART %q", ErrInvalidRRule, property.value)
+			}
+			recurrence.Start = times[0]
+		case "RRULE":
+			rules = append(rules, property)
+		case "RDATE":
+			rdates = append(rdates, property)
+		case "EXDATE":
+			exdates = append(exdates, property)
+		default:
+			return nil, fmt.Errorf("%w: unsupported property %q", ErrInvalidRRule, property.name)
+		}
+	}
+
+	if recurrence.Start.IsZero() {
+		return nil, fmt.Errorf("%w: DTSTART is required", ErrInvalidRRule)
+	}
+
+	location := recurrence.Start.Location()
+	for _, property := range rules {
+		rule, err := parseRRule(property.value, location)
+		if err != nil {
+			return nil, err
+		}
+		recurrence.Rules = append(recurrence.Rules, rule)
+	}
+	for _, property := range rdates {
+		times, err := property.times(location)
+		if err != nil {
+			return nil, err
+		}
+		recurrence.RDates = append(recurrence.RDates, times...)
+	}
+	for _, property := range exdates {
+		times, err := property.times(location)
+		if err != nil {
+			return nil, err
+		}
+		recurrence.ExDates = append(recurrence.ExDates, times...)
+	}
+
+	return recurrence, nil
+}
+
+// String serializes the recurrence set in the form read by ParseRecurrence, one property per line.
+// Times are written with the TZID of Start's location, or in UTC when Start is in UTC or in time.Local, which has no
+// portable time zone name.
+func (r *Recurrence) String() string {
+	location := r.Start.Location()
+	if location == time.Local {
+		location = time.UTC
+	}
+	lines := []string{formatICalProperty("DTSTART", []time.Time{r.Start}, location)}
+
+	for _, rule := range r.Rules {
+		lines = append(lines, "RRULE:"+rule.String())
+	}
+	if len(r.RDates) > 0 {
+		lines = append(lines, formatICalProperty("RDATE", r.RDates, location))
+	}
+	if len(r.ExDates) > 0 {
+		lines = append(lines, formatICalProperty("EXDATE", r.ExDates, location))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// All returns an iterator over the occurrences of the set in chronological order, without duplicates.
+// Occurrences are computed lazily; sets with unbounded rules are unbounded, so stop iterating or use Between.
+func (r *Recurrence) All() iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		excluded := make(map[[2]int64]bool, len(r.ExDates))
+		for _, exdate := range r.ExDates {
+			excluded[instantKey(exdate)] = true
+		}
+
+		extra := append([]time.Time{r.Start}, r.RDates...)
+		slices.SortFunc(extra, time.Time.Compare)
+
+		sources := []iter.Seq[time.Time]{slices.Values(extra)}
+		for _, rule := range r.Rules {
+			sources = append(sources, rule.All(r.Start))
+		}
+
+		var previous time.Time
+		for occurrence := range mergeSorted(sources) {
+			if occurrence.Equal(previous) || excluded[instantKey(occurrence)] {
+				continue
+			}
+			previous = occurrence
+			if !yield(occurrence.In(r.Start.Location())) {
+				return
+			}
+		}
+	}
+}
+
+// Between returns an iterator over the occurrences of the set that lie in [from, to).
+func (r *Recurrence) Between(from, to time.Time) iter.Seq[time.Time] {
+	return betweenSeq(r.All(), from, to)
+}
+
+// mergeSorted merges sorted sequences into one sorted sequence, pulling from each lazily.
+func mergeSorted(sources []iter.Seq[time.Time]) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		type head struct {
+			next  func() (time.Time, bool)
+			stop  func()
+			value time.Time
+			ok    bool
+		}
+
+		heads := make([]*head, 0, len(sources))
+		defer func() {
+			for _, h := range heads {
+				h.stop()
+			}
+		}()
+
+		for _, source := range sources {
+			next, stop := iter.Pull(source)
+			value, ok := next()
+			heads = append(heads, &head{next: next, stop: stop, value: value, ok: ok})
+		}
+
+		for {
+			var earliest *head
+			for _, candidate := range heads {
+				if candidate.ok && (earliest == nil || candidate.value.Before(earliest.value)) {
+					earliest = candidate
+				}
+			}
+			if earliest == nil || !yield(earliest.value) {
+				return
+			}
+			earliest.value, earliest.ok = earliest.next()
+		}
+	}
+}
+
+func instantKey(t time.Time) [2]int64 {
+	return [2]int64{t.Unix(), int64(t.Nanosecond())}
+}
+
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func unfoldICalLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func parseICalProperty(line string) (icalProperty, error) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		// a bare rule such as "FREQ=DAILY;COUNT=3" is accepted as an RRULE.
+		if strings.HasPrefix(strings.ToUpper(line), "FREQ=") {
+			return icalProperty{name: "RRULE", value: line}, nil
+		}
+		return icalProperty{}, fmt.Errorf("%w: invalid line %q", ErrInvalidRRule, line)
+	}
+
+	parts := strings.Split(head, ";")
+	property := icalProperty{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: value}
+	for _, param := range parts[1:] {
+		key, paramValue, _ := strings.Cut(param, "=")
+		property.params[strings.ToUpper(key)] = strings.Trim(paramValue, `"`)
+	}
+	return property, nil
+}
+
+// times parses the comma-separated DATE or DATE-TIME values of the property, using its TZID or location.
+func (p icalProperty) times(location *time.Location) ([]time.Time, error) {
+	if tzid, ok := p.params["TZID"]; ok {
+		loaded, err := time.LoadLocation(tzid)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRRule, err)
+		}
+		location = loaded
+	}
+	if valueType := p.params["VALUE"]; valueType != "" && valueType != "DATE" && valueType != "DATE-TIME" {
+		return nil, fmt.Errorf("%w: unsupported %s value type %q", ErrInvalidRRule, p.name, valueType)
+	}
+
+	var times []time.Time
+	for _, value := range strings.Split(p.value, ",") {
+		parsed, err := parseICalTime(value, location, false)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidRRule, p.name, err)
+		}
+		times = append(times, parsed)
+	}
+	return times, nil
+}
+
+// parseICalTime parses a DATE or DATE-TIME value. Dates resolve to the start of the day, or to its last instant when
+// endOfDay is set, as for an inclusive UNTIL.
+func parseICalTime(value string, location *time.Location, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	switch len(value) {
+	case len(icalUTCLayout):
+		return time.Parse(icalUTCLayout, value)
+	case len(icalLocalLayout):
+		return time.ParseInLocation(icalLocalLayout, value, location)
+	case len(icalDateLayout):
+		date, err := time.ParseInLocation(icalDateLayout, value, location)
+		if err == nil && endOfDay {
+			date = EndOfDay(date)
+		}
+		return date, err
+	default:
+		return time.Time{}, fmt.Errorf("invalid date or time %q", value)
+	}
+}
+
+func formatICalProperty(name string, times []time.Time, location *time.Location) string {
+	values := make([]string, 0, len(times))
+	for _, t := range times {
+		if location == time.UTC {
+			values = append(values, t.UTC().Format(icalUTCLayout))
+		} else {
+			values = append(values, t.In(location).Format(icalLocalLayout))
+		}
+	}
+
+	if location == time.UTC {
+		return name + ":" + strings.Join(values, ",")
+	}
+	return name + ";TZID=" + location.String() + ":" + strings.Join(values, ",")
+}
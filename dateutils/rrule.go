@@ -0,0 +1,657 @@
+package dateutils
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRRule is returned when a recurrence rule or recurrence set cannot be parsed.
+var ErrInvalidRRule = errors.New("dateutils: invalid recurrence rule")
+
+// Frequency - the FREQ of a recurrence rule.
+type Frequency int
+
+const (
+	Secondly Frequency = iota + 1
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{
+	Secondly: "SECONDLY",
+	Minutely: "MINUTELY",
+	Hourly:   "HOURLY",
+	Daily:    "DAILY",
+	Weekly:   "WEEKLY",
+	Monthly:  "MONTHLY",
+	Yearly:   "YEARLY",
+}
+
+// String returns the RFC 5545 name of the frequency, e.g. "WEEKLY".
+func (f Frequency) String() string {
+	if name, ok := frequencyNames[f]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+var rruleWeekdays = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is a BYDAY entry: a weekday with an optional ordinal, such as the last Friday (-1FR).
+// N is 0 for every occurrence of the weekday within the period.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// String formats the entry in RFC 5545 form, e.g. "MO", "1FR" or "-2SU".
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return rruleWeekdays[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + rruleWeekdays[w.Weekday]
+}
+
+// RRule is an RFC 5545 recurrence rule.
+// WeekStart is the first day of the week (WKST); ParseRRule defaults it to Monday as RFC 5545 requires, so set it
+// explicitly when building rules in code. Zero Interval is treated as 1; zero Count and Until mean no limit.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	BySecond   []int
+	ByMinute   []int
+	ByHour     []int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByYearDay  []int
+	ByWeekNo   []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// ParseRRule parses an RRULE value such as "FREQ=MONTHLY;BYDAY=-1FR;COUNT=6", with or without the "RRULE:" prefix.
+// UNTIL values without a UTC designator are interpreted in UTC; ParseRecurrence interprets them in the location of
+// DTSTART instead.
+func ParseRRule(value string) (*RRule, error) {
+	return parseRRule(value, time.UTC)
+}
+
+func parseRRule(value string, location *time.Location) (*RRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	rule := &RRule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !ok || seen[key] {
+			return nil, fmt.Errorf("%w: invalid or repeated part %q", ErrInvalidRRule, part)
+		}
+		seen[key] = true
+
+		if err := rule.setPart(key, strings.ToUpper(strings.TrimSpace(val)), location); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidRRule, key, err)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *RRule) setPart(key, value string, location *time.Location) error {
+	var err error
+	switch key {
+	case "FREQ":
+		r.Freq, err = parseFrequency(value)
+	case "INTERVAL":
+		r.Interval, err = parseRuleInt(value, 1, 0)
+	case "COUNT":
+		r.Count, err = parseRuleInt(value, 1, 0)
+	case "UNTIL":
+		r.Until, err = parseICalTime(value, location, true)
+	case "BYSECOND":
+		r.BySecond, err = parseRuleInts(value, 0, 60, false)
+	case "BYMINUTE":
+		r.ByMinute, err = parseRuleInts(value, 0, 59, false)
+	case "BYHOUR":
+		r.ByHour, err = parseRuleInts(value, 0, 23, false)
+	case "BYDAY":
+		r.ByDay, err = parseWeekdayNums(value)
+	case "BYMONTHDAY":
+		r.ByMonthDay, err = parseRuleInts(value, 1, 31, true)
+	case "BYYEARDAY":
+		r.ByYearDay, err = parseRuleInts(value, 1, 366, true)
+	case "BYWEEKNO":
+		r.ByWeekNo, err = parseRuleInts(value, 1, 53, true)
+	case "BYMONTH":
+		var months []int
+		months, err = parseRuleInts(value, 1, 12, false)
+		for _, month := range months {
+			r.ByMonth = append(r.ByMonth, time.Month(month))
+		}
+	case "BYSETPOS":
+		r.BySetPos, err = parseRuleInts(value, 1, 366, true)
+	case "WKST":
+		r.WeekStart, err = parseRuleWeekday(value)
+	default:
+		err = errors.New("unsupported rule part")
+	}
+	return err
+}
+
+func (r *RRule) validate() error {
+	switch {
+	case r.Freq == 0:
+		return fmt.Errorf("%w: FREQ is required", ErrInvalidRRule)
+	case r.Count > 0 && !r.Until.IsZero():
+		return fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRRule)
+	case len(r.ByWeekNo) > 0 && r.Freq != Yearly:
+		return fmt.Errorf("%w: BYWEEKNO requires FREQ=YEARLY", ErrInvalidRRule)
+	case len(r.ByYearDay) > 0 && (r.Freq == Daily || r.Freq == Weekly || r.Freq == Monthly):
+		return fmt.Errorf("%w: BYYEARDAY is not allowed with FREQ=%s", ErrInvalidRRule, r.Freq)
+	case len(r.ByMonthDay) > 0 && r.Freq == Weekly:
+		return fmt.Errorf("%w: BYMONTHDAY is not allowed with FREQ=WEEKLY", ErrInvalidRRule)
+	}
+
+	for _, day := range r.ByDay {
+		if day.N != 0 && (r.Freq < Monthly || (r.Freq == Yearly && len(r.ByWeekNo) > 0)) {
+			return fmt.Errorf("%w: BYDAY ordinals require FREQ=MONTHLY or FREQ=YEARLY without BYWEEKNO", ErrInvalidRRule)
+		}
+	}
+	return nil
+}
+
+func parseFrequency(value string) (Frequency, error) {
+	for frequency, name := range frequencyNames {
+		if name == value {
+			return frequency, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown frequency %q", value)
+}
+
+func parseRuleInt(value string, minimum, maximum int) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if number < minimum || (maximum > 0 && number > maximum) {
+		return 0, fmt.Errorf("%d is out of range", number)
+	}
+	return number, nil
+}
+
+func parseRuleInts(value string, minimum, maximum int, allowNegative bool) ([]int, error) {
+	var numbers []int
+	for _, item := range strings.Split(value, ",") {
+		number, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		magnitude := number
+		if allowNegative && number < 0 {
+			magnitude = -number
+		}
+		if magnitude < minimum || magnitude > maximum {
+			return nil, fmt.Errorf("%d is out of range", number)
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+func parseRuleWeekday(value string) (time.Weekday, error) {
+	if index := slices.Index(rruleWeekdays[:], value); index >= 0 {
+		return time.Weekday(index), nil
+	}
+	return 0, fmt.Errorf("unknown weekday %q", value)
+}
+
+func parseWeekdayNums(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		weekday, err := parseRuleWeekday(item[len(item)-2:])
+		if err != nil {
+			return nil, err
+		}
+
+		n := 0
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid ordinal %q", ordinal)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Weekday: weekday})
+	}
+	return days, nil
+}
+
+// String serializes the rule as an RRULE value without the "RRULE:" prefix. UNTIL is written in UTC, except that an
+// Until at the last instant of a day, as parsed from a DATE value or returned by EndOfDay, is written as a DATE.
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+
+	switch {
+	case r.Until.IsZero():
+	case r.Until.Equal(EndOfDay(r.Until)):
+		parts = append(parts, "UNTIL="+r.Until.Format(icalDateLayout))
+	default:
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(icalUTCLayout))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	parts = appendRulePart(parts, "BYSECOND", r.BySecond)
+	parts = appendRulePart(parts, "BYMINUTE", r.ByMinute)
+	parts = appendRulePart(parts, "BYHOUR", r.ByHour)
+	parts = appendRulePart(parts, "BYDAY", r.ByDay)
+	parts = appendRulePart(parts, "BYMONTHDAY", r.ByMonthDay)
+	parts = appendRulePart(parts, "BYYEARDAY", r.ByYearDay)
+	parts = appendRulePart(parts, "BYWEEKNO", r.ByWeekNo)
+
+	months := make([]int, 0, len(r.ByMonth))
+	for _, month := range r.ByMonth {
+		months = append(months, int(month))
+	}
+	parts = appendRulePart(parts, "BYMONTH", months)
+	parts = appendRulePart(parts, "BYSETPOS", r.BySetPos)
+
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+rruleWeekdays[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+func appendRulePart[T any](parts []string, key string, values []T) []string {
+	if len(values) == 0 {
+		return parts
+	}
+	items := make([]string, 0, len(values))
+	for _, value := range values {
+		items = append(items, fmt.Sprint(value))
+	}
+	return append(parts, key+"="+strings.Join(items, ","))
+}
+
+// All returns an iterator over the occurrences of the rule starting at start (DTSTART), in start's location.
+// Occurrences are computed lazily; rules without COUNT or UNTIL are unbounded, so stop iterating or use Between.
+// As in most implementations, start itself is only yielded if it matches the rule.
+func (r *RRule) All(start time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		expansion := newRRuleExpansion(r, start)
+		count := 0
+		lastYear := start.Year()
+
+		for period := 0; ; period = expansion.nextPeriod(period) {
+			candidates, ok := expansion.period(period)
+			if !ok {
+				return
+			}
+			if len(candidates) == 0 && expansion.periodYear(period) > lastYear+rruleSearchYears {
+				return
+			}
+			for _, candidate := range candidates {
+				if candidate.Before(start) {
+					continue
+				}
+				if !r.Until.IsZero() && candidate.After(r.Until) {
+					return
+				}
+				if !yield(candidate) {
+					return
+				}
+				lastYear = candidate.Year()
+				if count++; r.Count > 0 && count >= r.Count {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Between returns an iterator over the occurrences of the rule starting at start that lie in [from, to).
+func (r *RRule) Between(start, from, to time.Time) iter.Seq[time.Time] {
+	return betweenSeq(r.All(start), from, to)
+}
+
+func betweenSeq(seq iter.Seq[time.Time], from, to time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		for occurrence := range seq {
+			if !occurrence.Before(to) {
+				return
+			}
+			if !occurrence.Before(from) && !yield(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// maxRRuleYear bounds the expansion of rules, since later years cannot be formatted in RFC 5545.
+const maxRRuleYear = 9999
+
+// rruleSearchYears bounds the search for the next occurrence, so that rules whose filters never match, such as
+// "FREQ=HOURLY;BYMONTH=2;BYMONTHDAY=30", terminate.
+const rruleSearchYears = 100
+
+// rruleExpansion holds a rule with the RFC 5545 defaults derived from DTSTART applied.
+type rruleExpansion struct {
+	rule     RRule
+	start    time.Time
+	location *time.Location
+	times    []clockTime
+}
+
+type clockTime struct {
+	hour, minute, second int
+}
+
+func newRRuleExpansion(r *RRule, start time.Time) *rruleExpansion {
+	rule := *r
+	rule.Interval = max(rule.Interval, 1)
+
+	noDayFilters := len(rule.ByWeekNo) == 0 && len(rule.ByYearDay) == 0 && len(rule.ByMonthDay) == 0 &&
+		len(rule.ByDay) == 0
+	if noDayFilters {
+		switch rule.Freq {
+		case Yearly:
+			if len(rule.ByMonth) == 0 {
+				rule.ByMonth = []time.Month{start.Month()}
+			}
+			rule.ByMonthDay = []int{start.Day()}
+		case Monthly:
+			rule.ByMonthDay = []int{start.Day()}
+		case Weekly:
+			rule.ByDay = []WeekdayNum{{Weekday: start.Weekday()}}
+		default:
+		}
+	}
+
+	expansion := &rruleExpansion{rule: rule, start: start, location: start.Location()}
+	if rule.Freq >= Daily {
+		expansion.times = expansion.clockTimes()
+	}
+	return expansion
+}
+
+func (e *rruleExpansion) clockTimes() []clockTime {
+	hours := orDefault(e.rule.ByHour, e.start.Hour())
+	minutes := orDefault(e.rule.ByMinute, e.start.Minute())
+	seconds := orDefault(e.rule.BySecond, e.start.Second())
+
+	var times []clockTime
+	for _, hour := range hours {
+		for _, minute := range minutes {
+			for _, second := range seconds {
+				times = append(times, clockTime{hour, minute, second})
+			}
+		}
+	}
+	slices.SortFunc(times, func(a, b clockTime) int {
+		return (a.hour*3600 + a.minute*60 + a.second) - (b.hour*3600 + b.minute*60 + b.second)
+	})
+	return times
+}
+
+func orDefault(values []int, fallback int) []int {
+	if len(values) == 0 {
+		return []int{fallback}
+	}
+	return values
+}
+
+// period returns the sorted occurrences of the nth period, after BYSETPOS. It returns false past maxRRuleYear.
+func (e *rruleExpansion) period(n int) ([]time.Time, bool) {
+	var candidates []time.Time
+
+	if e.rule.Freq >= Daily {
+		first, last := e.periodDays(n)
+		if first.Year() > maxRRuleYear {
+			return nil, false
+		}
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			if !e.matchesDay(day) {
+				continue
+			}
+			for _, clock := range e.times {
+				candidates = append(candidates, wallTime(day, clock, e.location))
+			}
+		}
+	} else {
+		periodStart := e.subDailyStart(n)
+		if periodStart.Year() > maxRRuleYear {
+			return nil, false
+		}
+		candidates = e.subDailyCandidates(periodStart)
+	}
+
+	slices.SortFunc(candidates, time.Time.Compare)
+	return e.applySetPos(candidates), true
+}
+
+// periodYear returns the year in which the nth period starts.
+func (e *rruleExpansion) periodYear(n int) int {
+	if e.rule.Freq >= Daily {
+		first, _ := e.periodDays(n)
+		return first.Year()
+	}
+	return e.subDailyStart(n).In(e.location).Year()
+}
+
+// nextPeriod returns the index of the period to expand after the nth. Sub-daily periods on days that fail the day
+// filters are skipped up to the next day, rather than expanded one hour, minute or second at a time.
+func (e *rruleExpansion) nextPeriod(n int) int {
+	if e.rule.Freq >= Daily {
+		return n + 1
+	}
+
+	periodStart := e.subDailyStart(n)
+	year, month, day := periodStart.In(e.location).Date()
+	if e.matchesDay(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)) {
+		return n + 1
+	}
+
+	length := time.Duration(e.rule.Interval) * e.subDailyStep()
+	untilNextDay := startOfDate(year, month, day+1, e.location).Sub(periodStart)
+	return n + max(int((untilNextDay+length-1)/length), 1)
+}
+
+func (e *rruleExpansion) subDailyStep() time.Duration {
+	return map[Frequency]time.Duration{Hourly: time.Hour, Minutely: time.Minute, Secondly: time.Second}[e.rule.Freq]
+}
+
+// subDailyStart returns the start of the nth period for hourly, minutely and secondly frequencies.
+func (e *rruleExpansion) subDailyStart(n int) time.Time {
+	step := e.subDailyStep()
+	return truncateClock(e.start, step).Add(time.Duration(n*e.rule.Interval) * step)
+}
+
+// periodDays returns the first and last day (as UTC midnights) of the nth period for daily and coarser frequencies.
+func (e *rruleExpansion) periodDays(n int) (time.Time, time.Time) {
+	year, month, day := e.start.Date()
+	offset := n * e.rule.Interval
+
+	switch e.rule.Freq {
+	case Yearly:
+		first := time.Date(year+offset, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(1, 0, -1)
+	case Monthly:
+		first := time.Date(year, month+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(0, 1, -1)
+	case Weekly:
+		first := time.Date(year, month, day-daysSinceWeekStart(e.start.Weekday(), e.rule.WeekStart)+7*offset,
+			0, 0, 0, 0, time.UTC)
+		return first, first.AddDate(0, 0, 6)
+	default:
+		first := time.Date(year, month, day+offset, 0, 0, 0, 0, time.UTC)
+		return first, first
+	}
+}
+
+func (e *rruleExpansion) subDailyCandidates(periodStart time.Time) []time.Time {
+	local := periodStart.In(e.location)
+	year, month, day := local.Date()
+	if !e.matchesDay(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)) {
+		return nil
+	}
+	if len(e.rule.ByHour) > 0 && !slices.Contains(e.rule.ByHour, local.Hour()) {
+		return nil
+	}
+
+	minutes := []int{0}
+	if e.rule.Freq == Hourly {
+		minutes = orDefault(e.rule.ByMinute, e.start.Minute())
+	} else if len(e.rule.ByMinute) > 0 && !slices.Contains(e.rule.ByMinute, local.Minute()) {
+		return nil
+	}
+
+	seconds := []int{0}
+	if e.rule.Freq != Secondly {
+		seconds = orDefault(e.rule.BySecond, e.start.Second())
+	} else if len(e.rule.BySecond) > 0 && !slices.Contains(e.rule.BySecond, local.Second()) {
+		return nil
+	}
+
+	var candidates []time.Time
+	for _, minute := range minutes {
+		for _, second := range seconds {
+			candidates = append(candidates, periodStart.Add(time.Duration(minute)*time.Minute+time.Duration(second)*time.Second))
+		}
+	}
+	return candidates
+}
+
+// matchesDay applies the BYMONTH, BYWEEKNO, BYYEARDAY, BYMONTHDAY and BYDAY filters to a day given as UTC midnight.
+func (e *rruleExpansion) matchesDay(day time.Time) bool {
+	rule := &e.rule
+
+	if len(rule.ByMonth) > 0 && !slices.Contains(rule.ByMonth, day.Month()) {
+		return false
+	}
+	if len(rule.ByWeekNo) > 0 && !e.matchesWeekNo(day) {
+		return false
+	}
+	if len(rule.ByYearDay) > 0 {
+		daysInYear := time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		if !matchesOrdinal(rule.ByYearDay, day.YearDay(), daysInYear) {
+			return false
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && !matchesOrdinal(rule.ByMonthDay, day.Day(), DaysInMonth(day)) {
+		return false
+	}
+	return len(rule.ByDay) == 0 || e.matchesWeekday(day)
+}
+
+func (e *rruleExpansion) matchesWeekday(day time.Time) bool {
+	// ordinals count within the month for MONTHLY rules and YEARLY rules with BYMONTH, otherwise within the year.
+	first := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+	if e.rule.Freq == Monthly || (e.rule.Freq == Yearly && len(e.rule.ByMonth) > 0) {
+		first = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		last = first.AddDate(0, 1, -1)
+	}
+
+	for _, weekday := range e.rule.ByDay {
+		if weekday.Weekday != day.Weekday() {
+			continue
+		}
+		switch {
+		case weekday.N == 0 || e.rule.Freq < Monthly:
+			return true
+		case weekday.N > 0 && int(day.Sub(first).Hours()/24)/7+1 == weekday.N:
+			return true
+		case weekday.N < 0 && int(last.Sub(day).Hours()/24)/7+1 == -weekday.N:
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekNo reports whether the day lies in one of the BYWEEKNO weeks, numbered like ISO 8601 but with WKST as
+// the first day of the week.
+func (e *rruleExpansion) matchesWeekNo(day time.Time) bool {
+	weekYear := day.Year()
+	week1 := e.firstWeekStart(weekYear)
+	if day.Before(week1) {
+		weekYear--
+		week1 = e.firstWeekStart(weekYear)
+	} else if next := e.firstWeekStart(weekYear + 1); !day.Before(next) {
+		weekYear++
+		week1 = next
+	}
+
+	week := int(day.Sub(week1).Hours()/24)/7 + 1
+	weeksInYear := int(e.firstWeekStart(weekYear+1).Sub(week1).Hours()/24) / 7
+	return matchesOrdinal(e.rule.ByWeekNo, week, weeksInYear)
+}
+
+// firstWeekStart returns the first day of week 1, the first week with at least four days in the year.
+func (e *rruleExpansion) firstWeekStart(year int) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	return jan4.AddDate(0, 0, -daysSinceWeekStart(jan4.Weekday(), e.rule.WeekStart))
+}
+
+// matchesOrdinal reports whether value (1-based) matches one of the ordinals, where negative ordinals count back
+// from total.
+func matchesOrdinal(ordinals []int, value, total int) bool {
+	for _, ordinal := range ordinals {
+		if ordinal == value || (ordinal < 0 && total+ordinal+1 == value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *rruleExpansion) applySetPos(candidates []time.Time) []time.Time {
+	if len(e.rule.BySetPos) == 0 {
+		return candidates
+	}
+
+	var selected []time.Time
+	for _, position := range e.rule.BySetPos {
+		index := position - 1
+		if position < 0 {
+			index = len(candidates) + position
+		}
+		if index >= 0 && index < len(candidates) && !slices.ContainsFunc(selected, candidates[index].Equal) {
+			selected = append(selected, candidates[index])
+		}
+	}
+	slices.SortFunc(selected, time.Time.Compare)
+	return selected
+}
+
+// wallTime returns the given wall clock time on day in location. Times skipped by a DST gap are interpreted with the
+// offset before the gap, as RFC 5545 requires, which moves them forward by the length of the gap.
+func wallTime(day time.Time, clock clockTime, location *time.Location) time.Time {
+	result := time.Date(day.Year(), day.Month(), day.Day(), clock.hour, clock.minute, clock.second, 0, location)
+	if result.Hour() == clock.hour && result.Minute() == clock.minute {
+		return result
+	}
+
+	_, offsetBefore := result.Add(-12 * time.Hour).Zone()
+	utc := time.Date(day.Year(), day.Month(), day.Day(), clock.hour, clock.minute, clock.second, 0, time.UTC)
+	return utc.Add(-time.Duration(offsetBefore) * time.Second).In(location)
+}
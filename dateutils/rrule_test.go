@@ -0,0 +1,395 @@
+package dateutils_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const rruleLayout = "2006-01-02 15:04"
+
+func take(t *testing.T, text string, limit int) []string {
+	t.Helper()
+	recurrence, err := dateutils.ParseRecurrence(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result []string
+	for occurrence := range recurrence.All() {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, occurrence.Format(rruleLayout))
+	}
+	return result
+}
+
+func dates(hourMinute string, days ...string) []string {
+	result := make([]string, 0, len(days))
+	for _, day := range days {
+		result = append(result, day+" "+hourMinute)
+	}
+	return result
+}
+
+// TestRRuleRFCExamples checks the examples of RFC 5545 section 3.8.5.3.
+func TestRRuleRFCExamples(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dtstart  string
+		rule     string
+		limit    int
+		expected []string
+	}{
+		{
+			"daily for 10 occurrences", "19970902T090000", "FREQ=DAILY;COUNT=10", 100,
+			dates("09:00", "1997-09-02", "1997-09-03", "1997-09-04", "1997-09-05", "1997-09-06",
+				"1997-09-07", "1997-09-08", "1997-09-09", "1997-09-10", "1997-09-11"),
+		},
+		{
+			"every 10 days, 5 occurrences", "19970902T090000", "FREQ=DAILY;INTERVAL=10;COUNT=5", 100,
+			dates("09:00", "1997-09-02", "1997-09-12", "1997-09-22", "1997-10-02", "1997-10-12"),
+		},
+		{
+			"every other day", "19970902T090000", "FREQ=DAILY;INTERVAL=2", 4,
+			dates("09:00", "1997-09-02", "1997-09-04", "1997-09-06", "1997-09-08"),
+		},
+		{
+			"weekly on Tuesday and Thursday for five weeks", "19970902T090000",
+			"FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH", 100,
+			dates("09:00", "1997-09-02", "1997-09-04", "1997-09-09", "1997-09-11", "1997-09-16",
+				"1997-09-18", "1997-09-23", "1997-09-25", "1997-09-30", "1997-10-02"),
+		},
+		{
+			"every other week on Monday, Wednesday and Friday", "19970901T090000",
+			"FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR", 100,
+			dates("09:00", "1997-09-01", "1997-09-03", "1997-09-05", "1997-09-15", "1997-09-17", "1997-09-19",
+				"1997-09-29", "1997-10-01", "1997-10-03", "1997-10-13", "1997-10-15", "1997-10-17", "1997-10-27",
+				"1997-10-29", "1997-10-31", "1997-11-10", "1997-11-12", "1997-11-14", "1997-11-24", "1997-11-26",
+				"1997-11-28", "1997-12-08", "1997-12-10", "1997-12-12", "1997-12-22"),
+		},
+		{
+			"monthly on the first Friday", "19970905T090000", "FREQ=MONTHLY;COUNT=10;BYDAY=1FR", 100,
+			dates("09:00", "1997-09-05", "1997-10-03", "1997-11-07", "1997-12-05", "1998-01-02",
+				"1998-02-06", "1998-03-06", "1998-04-03", "1998-05-01", "1998-06-05"),
+		},
+		{
+			"every other month on the first and last Sunday", "19970907T090000",
+			"FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU", 100,
+			dates("09:00", "1997-09-07", "1997-09-28", "1997-11-02", "1997-11-30", "1998-01-04",
+				"1998-01-25", "1998-03-01", "1998-03-29", "1998-05-03", "1998-05-31"),
+		},
+		{
+			"monthly on the second-to-last Monday", "19970922T090000", "FREQ=MONTHLY;COUNT=6;BYDAY=-2MO", 100,
+			dates("09:00", "1997-09-22", "1997-10-20", "1997-11-17", "1997-12-22", "1998-01-19", "1998-02-16"),
+		},
+		{
+			"monthly on the third-to-last day", "19970928T090000", "FREQ=MONTHLY;BYMONTHDAY=-3", 6,
+			dates("09:00", "1997-09-28", "1997-10-29", "1997-11-28", "1997-12-29", "1998-01-29", "1998-02-26"),
+		},
+		{
+			"monthly on the 2nd and 15th", "19970902T090000", "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15", 100,
+			dates("09:00", "1997-09-02", "1997-09-15", "1997-10-02", "1997-10-15", "1997-11-02",
+				"1997-11-15", "1997-12-02", "1997-12-15", "1998-01-02", "1998-01-15"),
+		},
+		{
+			"yearly in June and July", "19970610T090000", "FREQ=YEARLY;COUNT=10;BYMONTH=6,7", 100,
+			dates("09:00", "1997-06-10", "1997-07-10", "1998-06-10", "1998-07-10", "1999-06-10",
+				"1999-07-10", "2000-06-10", "2000-07-10", "2001-06-10", "2001-07-10"),
+		},
+		{
+			"every third year on the 1st, 100th and 200th day", "19970101T090000",
+			"FREQ=YEARLY;INTERVAL=3;COUNT=10;BYYEARDAY=1,100,200", 100,
+			dates("09:00", "1997-01-01", "1997-04-10", "1997-07-19", "2000-01-01", "2000-04-09",
+				"2000-07-18", "2003-01-01", "2003-04-10", "2003-07-19", "2006-01-01"),
+		},
+		{
+			"every 20th Monday of the year", "19970519T090000", "FREQ=YEARLY;BYDAY=20MO", 3,
+			dates("09:00", "1997-05-19", "1998-05-18", "1999-05-17"),
+		},
+		{
+			"Monday of week number 20", "19970512T090000", "FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO", 3,
+			dates("09:00", "1997-05-12", "1998-05-11", "1999-05-17"),
+		},
+		{
+			"every Thursday in March", "19970313T090000", "FREQ=YEARLY;BYMONTH=3;BYDAY=TH", 8,
+			dates("09:00", "1997-03-13", "1997-03-20", "1997-03-27", "1998-03-05", "1998-03-12",
+				"1998-03-19", "1998-03-26", "1999-03-04"),
+		},
+		{
+			"the first Saturday after the first Sunday", "19970913T090000",
+			"FREQ=MONTHLY;BYDAY=SA;BYMONTHDAY=7,8,9,10,11,12,13", 10,
+			dates("09:00", "1997-09-13", "1997-10-11", "1997-11-08", "1997-12-13", "1998-01-10",
+				"1998-02-07", "1998-03-07", "1998-04-11", "1998-05-09", "1998-06-13"),
+		},
+		{
+			"US presidential election day", "19961105T090000",
+			"FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8", 3,
+			dates("09:00", "1996-11-05", "2000-11-07", "2004-11-02"),
+		},
+		{
+			"third instance of Tuesday, Wednesday or Thursday", "19970904T090000",
+			"FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3", 100,
+			dates("09:00", "1997-09-04", "1997-10-07", "1997-11-06"),
+		},
+		{
+			"second-to-last weekday of the month", "19970929T090000",
+			"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2", 7,
+			dates("09:00", "1997-09-29", "1997-10-30", "1997-11-27", "1997-12-30", "1998-01-29",
+				"1998-02-26", "1998-03-30"),
+		},
+		{
+			"every 15 minutes for 6 occurrences", "19970902T090000", "FREQ=MINUTELY;INTERVAL=15;COUNT=6", 100,
+			[]string{"1997-09-02 09:00", "1997-09-02 09:15", "1997-09-02 09:30", "1997-09-02 09:45",
+				"1997-09-02 10:00", "1997-09-02 10:15"},
+		},
+		{
+			"every hour and a half for 4 occurrences", "19970902T090000", "FREQ=MINUTELY;INTERVAL=90;COUNT=4", 100,
+			[]string{"1997-09-02 09:00", "1997-09-02 10:30", "1997-09-02 12:00", "1997-09-02 13:30"},
+		},
+		{
+			"every 20 minutes from 9:00 to 16:40", "19970902T090000",
+			"FREQ=DAILY;BYHOUR=9,10,11,12,13,14,15,16;BYMINUTE=0,20,40", 4,
+			[]string{"1997-09-02 09:00", "1997-09-02 09:20", "1997-09-02 09:40", "1997-09-02 10:00"},
+		},
+		{
+			"WKST=MO", "19970805T090000", "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO", 100,
+			dates("09:00", "1997-08-05", "1997-08-10", "1997-08-19", "1997-08-24"),
+		},
+		{
+			"WKST=SU", "19970805T090000", "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU", 100,
+			dates("09:00", "1997-08-05", "1997-08-17", "1997-08-19", "1997-08-31"),
+		},
+		{
+			"invalid dates are ignored", "20070115T090000", "FREQ=MONTHLY;BYMONTHDAY=15,30;COUNT=5", 100,
+			dates("09:00", "2007-01-15", "2007-01-30", "2007-02-15", "2007-03-15", "2007-03-30"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			text := "DTSTART;TZID=America/New_York:" + testCase.dtstart + "\nRRULE:" + testCase.rule
+			assert.Equal(t, testCase.expected, take(t, text, testCase.limit))
+		})
+	}
+
+	t.Run("daily until December 24", func(t *testing.T) {
+		occurrences := take(t, "DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=DAILY;UNTIL=19971224T000000Z", 1000)
+		assert.Len(t, occurrences, 113)
+		assert.Equal(t, "1997-12-23 09:00", occurrences[len(occurrences)-1])
+	})
+
+	t.Run("every day in January for 3 years", func(t *testing.T) {
+		yearly := take(t, "DTSTART;TZID=America/New_York:19980101T090000\n"+
+			"RRULE:FREQ=YEARLY;UNTIL=20000131T140000Z;BYMONTH=1;BYDAY=SU,MO,TU,WE,TH,FR,SA", 1000)
+		daily := take(t, "DTSTART;TZID=America/New_York:19980101T090000\n"+
+			"RRULE:FREQ=DAILY;UNTIL=20000131T140000Z;BYMONTH=1", 1000)
+		assert.Len(t, yearly, 93)
+		assert.Equal(t, yearly, daily)
+	})
+
+	t.Run("Friday the 13th with EXDATE", func(t *testing.T) {
+		text := "DTSTART;TZID=America/New_York:19970902T090000\n" +
+			"EXDATE;TZID=America/New_York:19970902T090000\n" +
+			"RRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13"
+		assert.Equal(t, dates("09:00", "1998-02-13", "1998-03-13", "1998-11-13", "1999-08-13", "2000-10-13"), take(t, text, 5))
+	})
+
+	t.Run("keeps wall clock time across DST", func(t *testing.T) {
+		text := "DTSTART;TZID=America/New_York:19971025T090000\nRRULE:FREQ=DAILY;COUNT=3"
+		recurrence, err := dateutils.ParseRecurrence(text)
+		assert.NoError(t, err)
+
+		var offsets []string
+		for occurrence := range recurrence.All() {
+			offsets = append(offsets, occurrence.Format(time.RFC3339))
+		}
+		assert.Equal(t, []string{"1997-10-25T09:00:00-04:00", "1997-10-26T09:00:00-05:00", "1997-10-27T09:00:00-05:00"}, offsets)
+	})
+
+	t.Run("times skipped by DST move forward", func(t *testing.T) {
+		text := "DTSTART;TZID=America/New_York:20240309T023000\nRRULE:FREQ=DAILY;COUNT=3"
+		assert.Equal(t, []string{"2024-03-09 02:30", "2024-03-10 03:30", "2024-03-11 02:30"}, take(t, text, 10))
+	})
+}
+
+func TestRecurrence(t *testing.T) {
+	text := strings.Join([]string{
+		"DTSTART:20240101T100000Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+		"RDATE:20240106T100000Z,20240103T100000Z",
+		"EXDATE:20240110T100000Z",
+	}, "\n")
+
+	recurrence, err := dateutils.ParseRecurrence(text)
+	assert.NoError(t, err)
+
+	t.Run("merges rules and dates", func(t *testing.T) {
+		assert.Equal(t, dates("10:00", "2024-01-01", "2024-01-03", "2024-01-06", "2024-01-08", "2024-01-15"), take(t, text, 5))
+	})
+
+	t.Run("Between", func(t *testing.T) {
+		from := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 1, 22, 10, 0, 0, 0, time.UTC)
+
+		var actual []string
+		for occurrence := range recurrence.Between(from, to) {
+			actual = append(actual, occurrence.Format(rruleLayout))
+		}
+		assert.Equal(t, dates("10:00", "2024-01-08", "2024-01-15", "2024-01-17"), actual)
+
+		rule := recurrence.Rules[0]
+		assert.Len(t, slices.Collect(rule.Between(recurrence.Start, from, to)), 4)
+	})
+
+	t.Run("String round trip", func(t *testing.T) {
+		serialized := recurrence.String()
+		assert.Equal(t, strings.Join([]string{
+			"DTSTART:20240101T100000Z",
+			"RRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+			"RDATE:20240106T100000Z,20240103T100000Z",
+			"EXDATE:20240110T100000Z",
+		}, "\n"), serialized)
+
+		reparsed, err := dateutils.ParseRecurrence(serialized)
+		assert.NoError(t, err)
+		assert.Equal(t, take(t, text, 20), take(t, reparsed.String(), 20))
+	})
+
+	t.Run("TZID round trip", func(t *testing.T) {
+		text := "DTSTART;TZID=Europe/Berlin:20240101T090000\nRRULE:FREQ=DAILY;COUNT=2"
+		recurrence, err := dateutils.ParseRecurrence(text)
+		assert.NoError(t, err)
+		assert.Equal(t, text, recurrence.String())
+	})
+
+	t.Run("DATE UNTIL round trip", func(t *testing.T) {
+		text := "DTSTART;TZID=Europe/Berlin:20240101T090000\nRRULE:FREQ=DAILY;UNTIL=20240103"
+		recurrence, err := dateutils.ParseRecurrence(text)
+		assert.NoError(t, err)
+		assert.Equal(t, text, recurrence.String())
+		assert.Len(t, take(t, text, 10), 3)
+		assert.Len(t, take(t, recurrence.String(), 10), 3)
+	})
+
+	t.Run("Local location round trip", func(t *testing.T) {
+		local := &dateutils.Recurrence{
+			Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.Local),
+			Rules: []*dateutils.RRule{{Freq: dateutils.Daily, Count: 2, WeekStart: time.Monday}},
+		}
+		serialized := local.String()
+		assert.Equal(t, "DTSTART:"+local.Start.UTC().Format("20060102T150405Z")+"\nRRULE:FREQ=DAILY;COUNT=2", serialized)
+
+		reparsed, err := dateutils.ParseRecurrence(serialized)
+		assert.NoError(t, err)
+		assert.True(t, local.Start.Equal(reparsed.Start))
+		assert.Equal(t, serialized, reparsed.String())
+	})
+
+	for _, invalid := range []string{
+		"RRULE:FREQ=DAILY",
+		"DTSTART:2024\nRRULE:FREQ=DAILY",
+		"DTSTART:20240101T100000Z\nRRULE:FREQ=SOMETIMES",
+		"DTSTART;TZID=Mars/Olympus:20240101T100000\nRRULE:FREQ=DAILY",
+		"DTSTART:20240101T100000Z\nSUMMARY:Meeting",
+		"DTSTART:20240101T100000Z\nRDATE;VALUE=PERIOD:20240101T100000Z/PT1H",
+	} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := dateutils.ParseRecurrence(invalid)
+			assert.ErrorIs(t, err, dateutils.ErrInvalidRRule)
+		})
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=monthly;byday=-1fr;count=6", "FREQ=MONTHLY;COUNT=6;BYDAY=-1FR"},
+		{"FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8", "FREQ=YEARLY;INTERVAL=4;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8;BYMONTH=11"},
+		{"FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH", "FREQ=WEEKLY;UNTIL=19971007T000000Z;BYDAY=TU,TH;WKST=SU"},
+		{"FREQ=WEEKLY;UNTIL=19971007", "FREQ=WEEKLY;UNTIL=19971007"},
+		{"FREQ=WEEKLY;UNTIL=19971007T235959Z", "FREQ=WEEKLY;UNTIL=19971007T235959Z"},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2", "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2"},
+		{"FREQ=DAILY;BYHOUR=9,17;BYMINUTE=30;BYSECOND=0", "FREQ=DAILY;BYSECOND=0;BYMINUTE=30;BYHOUR=9,17"},
+		{"FREQ=YEARLY;BYWEEKNO=20,-1;BYYEARDAY=-1;BYDAY=MO", "FREQ=YEARLY;BYDAY=MO;BYYEARDAY=-1;BYWEEKNO=20,-1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			rule, err := dateutils.ParseRRule(testCase.input)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, rule.String())
+		})
+	}
+
+	for _, invalid := range []string{
+		"",
+		"COUNT=5",
+		"FREQ=DAILY;COUNT=5;UNTIL=20240101T000000Z",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYWEEKNO=1",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYYEARDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYSETPOS=0",
+		"FREQ=MONTHLY;UNKNOWN=1",
+	} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := dateutils.ParseRRule(invalid)
+			assert.ErrorIs(t, err, dateutils.ErrInvalidRRule)
+		})
+	}
+
+	t.Run("rule built in code", func(t *testing.T) {
+		rule := &dateutils.RRule{
+			Freq:      dateutils.Monthly,
+			Count:     3,
+			ByDay:     []dateutils.WeekdayNum{{N: -1, Weekday: time.Friday}},
+			WeekStart: time.Monday,
+		}
+		start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		assert.Equal(t, []string{"2024-01-26 12:00", "2024-02-23 12:00", "2024-03-29 12:00"},
+			formatAll(slices.Collect(rule.All(start)), rruleLayout))
+		assert.Equal(t, "FREQ=MONTHLY;COUNT=3;BYDAY=-1FR", rule.String())
+	})
+}
+
+func TestRRuleNeverMatches(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, text := range []string{
+		"FREQ=SECONDLY;BYMONTH=2;BYMONTHDAY=30",
+		"FREQ=HOURLY;BYMONTH=2;BYMONTHDAY=30",
+		"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+	} {
+		t.Run(text, func(t *testing.T) {
+			rule, err := dateutils.ParseRRule(text)
+			assert.NoError(t, err)
+
+			deadline := time.Now().Add(5 * time.Second)
+			assert.Empty(t, slices.Collect(rule.All(start)))
+			assert.Empty(t, slices.Collect(rule.Between(start, start, start.AddDate(10, 0, 0))))
+			assert.True(t, time.Now().Before(deadline), "search took too long")
+		})
+	}
+
+	// Occurrences after a long gap without matches are still found.
+	rule, err := dateutils.ParseRRule("FREQ=MINUTELY;INTERVAL=30;BYMONTH=2;BYMONTHDAY=29;BYHOUR=12;COUNT=3")
+	assert.NoError(t, err)
+	var occurrences []string
+	for occurrence := range rule.All(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		occurrences = append(occurrences, occurrence.Format(rruleLayout))
+	}
+	assert.Equal(t, []string{"2028-02-29 12:00", "2028-02-29 12:30", "2032-02-29 12:00"}, occurrences)
+}
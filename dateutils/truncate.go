@@ -0,0 +1,146 @@
+package dateutils
+
+import "time"
+
+// Unit - a calendar unit used to truncate and round times.
+type Unit int
+
+const (
+	Second Unit = iota + 1
+	Minute
+	Hour
+	Day
+	Week
+	Month
+	Quarter
+	Year
+)
+
+var unitNames = map[Unit]string{
+	Second:  "second",
+	Minute:  "minute",
+	Hour:    "hour",
+	Day:     "day",
+	Week:    "week",
+	Month:   "month",
+	Quarter: "quarter",
+	Year:    "year",
+}
+
+// String returns the lowercase name of the unit, e.g. "month".
+func (u Unit) String() string {
+	if name, ok := unitNames[u]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// TruncateTo returns the start of the calendar unit containing t, in t's location.
+// Unlike Floor, the location is preserved, and days that are 23 or 25 hours long because of DST are handled
+// correctly: a day starts at the first instant of its date, even if local midnight is skipped.
+// Weeks start on Sunday, matching StartOfWeek. Unknown units return t unchanged.
+func TruncateTo(t time.Time, unit Unit) time.Time {
+	year, month, day := t.Date()
+	location := t.Location()
+
+	switch unit {
+	case Second:
+		return truncateClock(t, time.Second)
+	case Minute:
+		return truncateClock(t, time.Minute)
+	case Hour:
+		return truncateClock(t, time.Hour)
+	case Day:
+		return startOfDate(year, month, day, location)
+	case Week:
+		return StartOfWeekOn(t, time.Sunday)
+	case Month:
+		return startOfDate(year, month, 1, location)
+	case Quarter:
+		return startOfDate(year, quarterStartMonth(month), 1, location)
+	case Year:
+		return startOfDate(year, time.January, 1, location)
+	default:
+		return t
+	}
+}
+
+// CeilTo returns the last instant (to the nanosecond) of the calendar unit containing t, in t's location.
+// It is the counterpart of TruncateTo: the result is one nanosecond before the start of the next unit.
+// Unknown units return t unchanged.
+func CeilTo(t time.Time, unit Unit) time.Time {
+	start := TruncateTo(t, unit)
+	year, month, day := start.Date()
+	location := start.Location()
+
+	var next time.Time
+	switch unit {
+	case Second:
+		next = start.Add(time.Second)
+	case Minute:
+		next = start.Add(time.Minute)
+	case Hour:
+		next = start.Add(time.Hour)
+	case Day:
+		next = startOfDate(year, month, day+1, location)
+	case Week:
+		next = startOfDate(year, month, day+7, location)
+	case Month:
+		next = startOfDate(year, month+1, 1, location)
+	case Quarter:
+		next = startOfDate(year, month+3, 1, location)
+	case Year:
+		next = startOfDate(year+1, time.January, 1, location)
+	default:
+		return t
+	}
+
+	return next.Add(-time.Nanosecond)
+}
+
+// truncateClock truncates t to a multiple of d on the local wall clock, keeping the UTC offset of t so that the
+// repeated hour after a DST fall-back truncates within the correct occurrence.
+func truncateClock(t time.Time, d time.Duration) time.Time {
+	_, offset := t.Zone()
+	step := int64(d / time.Second)
+
+	remainder := (t.Unix() + int64(offset)) % step
+	if remainder < 0 {
+		remainder += step
+	}
+
+	result := t.Add(-time.Duration(remainder)*time.Second - time.Duration(t.Nanosecond()))
+	if _, resultOffset := result.Zone(); resultOffset == offset {
+		return result
+	}
+
+	// the boundary lies on the other side of a transition; fall back to the wall clock.
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	switch d {
+	case time.Hour:
+		minute, second = 0, 0
+	case time.Minute:
+		second = 0
+	}
+	return time.Date(year, month, day, hour, minute, second, 0, t.Location())
+}
+
+// startOfDate returns the first instant of the given (possibly denormalized) date in location.
+// When local midnight falls into a DST gap, time.Date may resolve it to the previous day; the start of the day is
+// then the transition itself.
+func startOfDate(year int, month time.Month, day int, location *time.Location) time.Time {
+	result := time.Date(year, month, day, 0, 0, 0, 0, location)
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	if IsSameDay(result, midnight) {
+		return result
+	}
+
+	_, offset := result.Zone()
+	return midnight.Add(-time.Duration(offset) * time.Second).In(location)
+}
+
+func quarterStartMonth(month time.Month) time.Month {
+	return (month-1)/3*3 + 1
+}
@@ -0,0 +1,103 @@
+package dateutils_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestTruncateTo(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+	saoPaulo := mustLoadLocation(t, "America/Sao_Paulo")
+
+	testCases := []struct {
+		name     string
+		input    time.Time
+		unit     dateutils.Unit
+		expected string
+	}{
+		{"second", time.Date(2024, 5, 15, 13, 45, 30, 999, newYork), dateutils.Second, "2024-05-15T13:45:30-04:00"},
+		{"minute", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Minute, "2024-05-15T13:45:00-04:00"},
+		{"hour", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Hour, "2024-05-15T13:00:00-04:00"},
+		{"hour with half-hour offset", time.Date(2024, 5, 15, 13, 45, 0, 0, kolkata), dateutils.Hour, "2024-05-15T13:00:00+05:30"},
+		{"day", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Day, "2024-05-15T00:00:00-04:00"},
+		{"day after spring forward", time.Date(2024, 3, 10, 12, 0, 0, 0, newYork), dateutils.Day, "2024-03-10T00:00:00-05:00"},
+		{"day after fall back", time.Date(2024, 11, 3, 12, 0, 0, 0, newYork), dateutils.Day, "2024-11-03T00:00:00-04:00"},
+		{"day with skipped midnight", time.Date(2018, 11, 4, 12, 0, 0, 0, saoPaulo), dateutils.Day, "2018-11-04T01:00:00-02:00"},
+		{"week", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Week, "2024-05-12T00:00:00-04:00"},
+		{"week across DST", time.Date(2024, 3, 12, 9, 0, 0, 0, newYork), dateutils.Week, "2024-03-10T00:00:00-05:00"},
+		{"month", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Month, "2024-05-01T00:00:00-04:00"},
+		{"quarter", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Quarter, "2024-04-01T00:00:00-04:00"},
+		{"year", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Year, "2024-01-01T00:00:00-05:00"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual := dateutils.TruncateTo(testCase.input, testCase.unit)
+			assert.Equal(t, testCase.expected, actual.Format(time.RFC3339Nano))
+			assert.Equal(t, testCase.input.Location(), actual.Location())
+		})
+	}
+
+	t.Run("repeated hour after fall back", func(t *testing.T) {
+		second := time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC).In(newYork)
+		assert.Equal(t, "2024-11-03T01:30:00-05:00", second.Format(time.RFC3339))
+		assert.Equal(t, "2024-11-03T01:00:00-05:00", dateutils.TruncateTo(second, dateutils.Hour).Format(time.RFC3339))
+	})
+}
+
+func TestCeilTo(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	testCases := []struct {
+		name     string
+		input    time.Time
+		unit     dateutils.Unit
+		expected string
+	}{
+		{"minute", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Minute, "2024-05-15T13:45:59.999999999-04:00"},
+		{"hour", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Hour, "2024-05-15T13:59:59.999999999-04:00"},
+		{"day", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Day, "2024-05-15T23:59:59.999999999-04:00"},
+		{"day before spring forward", time.Date(2024, 3, 9, 13, 0, 0, 0, newYork), dateutils.Day, "2024-03-09T23:59:59.999999999-05:00"},
+		{"day of fall back", time.Date(2024, 11, 3, 13, 0, 0, 0, newYork), dateutils.Day, "2024-11-03T23:59:59.999999999-05:00"},
+		{"week", time.Date(2024, 5, 15, 13, 45, 30, 0, newYork), dateutils.Week, "2024-05-18T23:59:59.999999999-04:00"},
+		{"month", time.Date(2024, 2, 10, 0, 0, 0, 0, newYork), dateutils.Month, "2024-02-29T23:59:59.999999999-05:00"},
+		{"quarter", time.Date(2024, 11, 15, 0, 0, 0, 0, newYork), dateutils.Quarter, "2024-12-31T23:59:59.999999999-05:00"},
+		{"year", time.Date(2024, 5, 15, 0, 0, 0, 0, newYork), dateutils.Year, "2024-12-31T23:59:59.999999999-05:00"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.CeilTo(testCase.input, testCase.unit).Format(time.RFC3339Nano))
+		})
+	}
+
+	t.Run("day lengths across DST", func(t *testing.T) {
+		springForward := time.Date(2024, 3, 10, 12, 0, 0, 0, newYork)
+		fallBack := time.Date(2024, 11, 3, 12, 0, 0, 0, newYork)
+
+		length := func(t time.Time) time.Duration {
+			return dateutils.CeilTo(t, dateutils.Day).Sub(dateutils.TruncateTo(t, dateutils.Day)) + time.Nanosecond
+		}
+		assert.Equal(t, 23*time.Hour, length(springForward))
+		assert.Equal(t, 25*time.Hour, length(fallBack))
+	})
+}
+
+func TestUnitString(t *testing.T) {
+	assert.Equal(t, "quarter", dateutils.Quarter.String())
+	assert.Equal(t, "unknown", dateutils.Unit(0).String())
+}
@@ -0,0 +1,107 @@
+package dateutils
+
+import (
+	"strings"
+	"time"
+)
+
+// regionWeekStarts maps regions whose week does not start on Monday to their first weekday, following CLDR.
+var regionWeekStarts = buildRegionWeekStarts(map[time.Weekday]string{
+	time.Friday:   "MV",
+	time.Saturday: "AE AF BH DJ DZ EG IQ IR JO KW LY OM QA SD SY",
+	time.Sunday: "AG AS BD BR BS BT BW BZ CA CO DM DO ET GT GU HK HN ID IL IN JM JP KE KH KR LA MH MM MO MT MX MZ " +
+		"NI NP PA PE PH PK PR PT PY SA SG SV TH TT TW UM US VE VI WS YE ZA ZW",
+})
+
+func buildRegionWeekStarts(regionsByWeekday map[time.Weekday]string) map[string]time.Weekday {
+	result := make(map[string]time.Weekday)
+	for weekday, regions := range regionsByWeekday {
+		for _, region := range strings.Fields(regions) {
+			result[region] = weekday
+		}
+	}
+	return result
+}
+
+// WeekStartFor returns the first day of the week customary for a locale such as "en-US", "de_DE.UTF-8" or "GB".
+// The region decides; a locale without a region, or with an unknown one, defaults to Monday as in ISO 8601.
+func WeekStartFor(locale string) time.Weekday {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	tags := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+
+	region := ""
+	if len(tags) == 1 && len(tags[0]) == 2 && strings.ToUpper(tags[0]) == tags[0] {
+		region = tags[0]
+	}
+	for _, tag := range tags[min(1, len(tags)):] {
+		if len(tag) == 2 {
+			region = strings.ToUpper(tag)
+			break
+		}
+	}
+
+	if weekday, ok := regionWeekStarts[region]; ok {
+		return weekday
+	}
+	return time.Monday
+}
+
+// StartOfWeekOn returns the start of the week (00:00:00 on weekStart) containing the given time, in its timezone.
+func StartOfWeekOn(t time.Time, weekStart time.Weekday) time.Time {
+	year, month, day := t.Date()
+	return startOfDate(year, month, day-daysSinceWeekStart(t.Weekday(), weekStart), t.Location())
+}
+
+// EndOfWeekOn returns the end of the week (23:59:59.999999999 on the day before weekStart) containing the given time.
+func EndOfWeekOn(t time.Time, weekStart time.Weekday) time.Time {
+	year, month, day := t.Date()
+	nextWeek := startOfDate(year, month, day+7-daysSinceWeekStart(t.Weekday(), weekStart), t.Location())
+	return nextWeek.Add(-time.Nanosecond)
+}
+
+// ISOWeekNumber returns the ISO 8601 week number (1 to 53) of the given time.
+func ISOWeekNumber(t time.Time) int {
+	_, week := t.ISOWeek()
+	return week
+}
+
+// ISOWeekYear returns the ISO 8601 week-numbering year of the given time, which differs from the calendar year
+// for days in late December or early January.
+func ISOWeekYear(t time.Time) int {
+	year, _ := t.ISOWeek()
+	return year
+}
+
+// ISOWeekday returns the ISO 8601 day of the week, from 1 (Monday) to 7 (Sunday).
+func ISOWeekday(t time.Time) int {
+	return isoWeekday(t.Weekday())
+}
+
+// DateFromISOWeek returns 00:00:00 of the given weekday in the ISO week of isoYear, in location.
+// Weeks outside the year's range are normalized into adjacent years, like time.Date.
+func DateFromISOWeek(isoYear, week int, weekday time.Weekday, location *time.Location) time.Time {
+	// January 4th is always in week 1.
+	jan4 := time.Date(isoYear, time.January, 4, 0, 0, 0, 0, time.UTC)
+	day := 4 - (isoWeekday(jan4.Weekday()) - 1) + (week-1)*7 + isoWeekday(weekday) - 1
+
+	return startOfDate(isoYear, time.January, day, location)
+}
+
+// ISOWeeksInYear returns the number of ISO 8601 weeks (52 or 53) in the given ISO week-numbering year.
+func ISOWeeksInYear(isoYear int) int {
+	// December 28th is always in the last week of its ISO year.
+	_, week := time.Date(isoYear, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+func daysSinceWeekStart(weekday, weekStart time.Weekday) int {
+	return (int(weekday) - int(weekStart) + 7) % 7
+}
+
+func isoWeekday(weekday time.Weekday) int {
+	if weekday == time.Sunday {
+		return 7
+	}
+	return int(weekday)
+}
@@ -0,0 +1,165 @@
+package dateutils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekStartFor(t *testing.T) {
+	testCases := []struct {
+		locale   string
+		expected time.Weekday
+	}{
+		{"en-US", time.Sunday},
+		{"en_GB", time.Monday},
+		{"de_DE.UTF-8", time.Monday},
+		{"pt-BR", time.Sunday},
+		{"ar-EG", time.Saturday},
+		{"dv-MV", time.Friday},
+		{"zh-Hant-TW", time.Sunday},
+		{"US", time.Sunday},
+		{"fr", time.Monday},
+		{"", time.Monday},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.locale, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.WeekStartFor(testCase.locale))
+		})
+	}
+}
+
+func TestStartAndEndOfWeekOn(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	testCases := []struct {
+		name          string
+		input         time.Time
+		weekStart     time.Weekday
+		expectedStart string
+		expectedEnd   string
+	}{
+		{
+			"monday week", time.Date(2024, 5, 15, 13, 0, 0, 0, time.UTC), time.Monday,
+			"2024-05-13T00:00:00Z", "2024-05-19T23:59:59.999999999Z",
+		},
+		{
+			"monday week on sunday", time.Date(2024, 5, 19, 13, 0, 0, 0, time.UTC), time.Monday,
+			"2024-05-13T00:00:00Z", "2024-05-19T23:59:59.999999999Z",
+		},
+		{
+			"saturday week", time.Date(2024, 5, 15, 13, 0, 0, 0, time.UTC), time.Saturday,
+			"2024-05-11T00:00:00Z", "2024-05-17T23:59:59.999999999Z",
+		},
+		{
+			"across year boundary", time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), time.Monday,
+			"2024-12-30T00:00:00Z", "2025-01-05T23:59:59.999999999Z",
+		},
+		{
+			"across DST", time.Date(2024, 3, 11, 9, 0, 0, 0, newYork), time.Monday,
+			"2024-03-11T00:00:00-04:00", "2024-03-17T23:59:59.999999999-04:00",
+		},
+		{
+			"week containing DST", time.Date(2024, 3, 10, 9, 0, 0, 0, newYork), time.Monday,
+			"2024-03-04T00:00:00-05:00", "2024-03-10T23:59:59.999999999-04:00",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			start := dateutils.StartOfWeekOn(testCase.input, testCase.weekStart)
+			end := dateutils.EndOfWeekOn(testCase.input, testCase.weekStart)
+			assert.Equal(t, testCase.expectedStart, start.Format(time.RFC3339Nano))
+			assert.Equal(t, testCase.expectedEnd, end.Format(time.RFC3339Nano))
+			assert.Equal(t, testCase.weekStart, start.Weekday())
+		})
+	}
+}
+
+func TestISOWeek(t *testing.T) {
+	testCases := []struct {
+		date            string
+		expectedYear    int
+		expectedWeek    int
+		expectedWeekday int
+	}{
+		{"2008-12-28", 2008, 52, 7},
+		{"2008-12-29", 2009, 1, 1},
+		{"2010-01-03", 2009, 53, 7},
+		{"2010-01-04", 2010, 1, 1},
+		{"2020-12-31", 2020, 53, 4},
+		{"2021-01-03", 2020, 53, 7},
+		{"2021-01-04", 2021, 1, 1},
+		{"2024-12-29", 2024, 52, 7},
+		{"2024-12-30", 2025, 1, 1},
+		{"2026-01-01", 2026, 1, 4},
+		{"2027-01-03", 2026, 53, 7},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.date, func(t *testing.T) {
+			date := dateutils.MustParseDateWithLayout(testCase.date, time.DateOnly)
+
+			assert.Equal(t, testCase.expectedYear, dateutils.ISOWeekYear(date))
+			assert.Equal(t, testCase.expectedWeek, dateutils.ISOWeekNumber(date))
+			assert.Equal(t, testCase.expectedWeekday, dateutils.ISOWeekday(date))
+
+			weekday := time.Weekday(testCase.expectedWeekday % 7)
+			assert.Equal(t, date, dateutils.DateFromISOWeek(testCase.expectedYear, testCase.expectedWeek, weekday, time.UTC))
+		})
+	}
+}
+
+func TestDateFromISOWeek(t *testing.T) {
+	testCases := []struct {
+		isoYear  int
+		week     int
+		weekday  time.Weekday
+		expected string
+	}{
+		{2009, 1, time.Monday, "2008-12-29"},
+		{2009, 53, time.Sunday, "2010-01-03"},
+		{2015, 53, time.Friday, "2016-01-01"},
+		{2020, 1, time.Monday, "2019-12-30"},
+		{2025, 1, time.Wednesday, "2025-01-01"},
+		{2024, 53, time.Monday, "2024-12-30"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expected, func(t *testing.T) {
+			actual := dateutils.DateFromISOWeek(testCase.isoYear, testCase.week, testCase.weekday, time.UTC)
+			assert.Equal(t, testCase.expected, actual.Format(time.DateOnly))
+		})
+	}
+
+	t.Run("keeps location", func(t *testing.T) {
+		berlin := mustLoadLocation(t, "Europe/Berlin")
+		actual := dateutils.DateFromISOWeek(2024, 13, time.Sunday, berlin)
+		assert.Equal(t, "2024-03-31T00:00:00+01:00", actual.Format(time.RFC3339))
+	})
+}
+
+func TestISOWeeksInYear(t *testing.T) {
+	testCases := []struct {
+		isoYear  int
+		expected int
+	}{
+		{2004, 53},
+		{2009, 53},
+		{2015, 53},
+		{2019, 52},
+		{2020, 53},
+		{2021, 52},
+		{2024, 52},
+		{2026, 53},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(time.Date(testCase.isoYear, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006"), func(t *testing.T) {
+			assert.Equal(t, testCase.expected, dateutils.ISOWeeksInYear(testCase.isoYear))
+		})
+	}
+}
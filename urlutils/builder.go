@@ -0,0 +1,230 @@
+package urlutils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Goldziher/go-utils/stringutils"
+)
+
+// Builder constructs URLs fluently from a base URL.
+// Errors are collected while building and reported by URL and Build instead of panicking.
+// A Builder is not safe for concurrent use.
+type Builder struct {
+	u             *url.URL
+	query         url.Values
+	queryModified bool
+	errs          []error
+}
+
+// NewBuilder creates a Builder starting from the given base URL.
+// If the base URL cannot be parsed, the error is reported when the URL is built.
+func NewBuilder(base string) *Builder {
+	u, err := url.Parse(base)
+	if err != nil {
+		return &Builder{u: &url.URL{}, query: url.Values{}, errs: []error{err}}
+	}
+	return NewBuilderFromURL(u)
+}
+
+// NewBuilderFromURL creates a Builder starting from a copy of the given URL. The original URL is not modified.
+// A nil URL is reported as an error when the URL is built.
+func NewBuilderFromURL(u *url.URL) *Builder {
+	if u == nil {
+		return &Builder{u: &url.URL{}, query: url.Values{}, errs: []error{errors.New("urlutils: nil base URL")}}
+	}
+
+	copied := *u
+	if u.User != nil {
+		user := *u.User
+		copied.User = &user
+	}
+	return &Builder{u: &copied, query: copied.Query()}
+}
+
+func (b *Builder) addError(err error) *Builder {
+	b.errs = append(b.errs, err)
+	return b
+}
+
+// Scheme sets the URL scheme.
+func (b *Builder) Scheme(scheme string) *Builder {
+	b.u.Scheme = scheme
+	return b
+}
+
+// Host sets the host, which may include a port.
+func (b *Builder) Host(host string) *Builder {
+	b.u.Host = host
+	return b
+}
+
+// Port sets the port, keeping the current hostname. A port of 0 removes the port.
+func (b *Builder) Port(port int) *Builder {
+	if port < 0 || port > 65535 {
+		return b.addError(fmt.Errorf("urlutils: invalid port %d", port))
+	}
+
+	hostname := b.u.Hostname()
+	if port == 0 {
+		if strings.Contains(hostname, ":") {
+			hostname = "[" + hostname + "]"
+		}
+		b.u.Host = hostname
+		return b
+	}
+
+	b.u.Host = net.JoinHostPort(hostname, strconv.Itoa(port))
+	return b
+}
+
+// User sets the userinfo to the given username without a password.
+func (b *Builder) User(username string) *Builder {
+	b.u.User = url.User(username)
+	return b
+}
+
+// UserPassword sets the userinfo to the given username and password.
+func (b *Builder) UserPassword(username, password string) *Builder {
+	b.u.User = url.UserPassword(username, password)
+	return b
+}
+
+// ClearUser removes the userinfo.
+func (b *Builder) ClearUser() *Builder {
+	b.u.User = nil
+	return b
+}
+
+// Path appends path segments to the current path. Each segment is escaped, so a "/" inside a segment is
+// encoded as %2F rather than starting a new segment. Empty segments are skipped and slashes between
+// segments are never doubled. The dot segments "." and ".." are rejected, since they would move out of the path.
+func (b *Builder) Path(segments ...string) *Builder {
+	escaped := strings.TrimRight(b.u.EscapedPath(), "/")
+
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if segment == "." || segment == ".." {
+			return b.addError(fmt.Errorf("urlutils: invalid path segment %q", segment))
+		}
+		escaped += "/" + url.PathEscape(segment)
+	}
+
+	return b.setEscapedPath(escaped)
+}
+
+// SetPath replaces the path. The path is used as is, with slashes separating segments; use Path to append
+// individually escaped segments.
+func (b *Builder) SetPath(path string) *Builder {
+	if path != "" && !strings.HasPrefix(path, "/") && b.u.Host != "" {
+		path = "/" + path
+	}
+	b.u.Path = path
+	b.u.RawPath = ""
+	return b
+}
+
+func (b *Builder) setEscapedPath(escaped string) *Builder {
+	if escaped == "" && b.u.Host != "" {
+		escaped = "/"
+	}
+
+	unescaped, err := url.PathUnescape(escaped)
+	if err != nil {
+		return b.addError(err)
+	}
+
+	b.u.Path = unescaped
+	b.u.RawPath = escaped
+	return b
+}
+
+// SetQuery sets the query parameter to the given values, replacing any existing values.
+// Values are converted using stringutils.Stringify.
+func (b *Builder) SetQuery(key string, values ...any) *Builder {
+	b.query.Del(key)
+	return b.AddQuery(key, values...)
+}
+
+// AddQuery appends the given values to the query parameter.
+// Values are converted using stringutils.Stringify.
+func (b *Builder) AddQuery(key string, values ...any) *Builder {
+	for _, value := range values {
+		b.query.Add(key, stringutils.Stringify(value))
+	}
+	b.queryModified = true
+	return b
+}
+
+// RemoveQuery removes the given query parameters.
+func (b *Builder) RemoveQuery(keys ...string) *Builder {
+	for _, key := range keys {
+		b.query.Del(key)
+	}
+	b.queryModified = true
+	return b
+}
+
+// QueryStruct sets query parameters from a map or struct using the same encoding as QueryValues.
+// Parameters produced by the value replace existing parameters with the same key.
+func (b *Builder) QueryStruct(value any, opts ...QueryOptions) *Builder {
+	values, err := QueryValues(value, opts...)
+	if err != nil {
+		return b.addError(err)
+	}
+
+	for key, entries := range values {
+		b.query[key] = entries
+	}
+	b.queryModified = true
+	return b
+}
+
+// ClearQuery removes all query parameters.
+func (b *Builder) ClearQuery() *Builder {
+	b.query = url.Values{}
+	b.queryModified = true
+	return b
+}
+
+// Fragment sets the fragment, without the leading "#". An empty fragment removes it.
+func (b *Builder) Fragment(fragment string) *Builder {
+	b.u.Fragment = fragment
+	b.u.RawFragment = ""
+	return b
+}
+
+// Err returns the errors collected so far joined together, or nil.
+func (b *Builder) Err() error {
+	return errors.Join(b.errs...)
+}
+
+// URL returns the built URL or the errors collected while building.
+// The returned URL is a copy and modifying it does not affect the Builder.
+// The query is re-encoded with sorted keys only if it was modified through the Builder.
+func (b *Builder) URL() (*url.URL, error) {
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+
+	result := *b.u
+	if b.queryModified {
+		result.RawQuery = b.query.Encode()
+	}
+	return &result, nil
+}
+
+// Build returns the built URL as a string or the errors collected while building.
+func (b *Builder) Build() (string, error) {
+	u, err := b.URL()
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,303 @@
+package urlutils
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// publicSuffixListData is a snapshot of https://publicsuffix.org/list/public_suffix_list.dat.
+//
+//go:embed public_suffix_list.dat
+var publicSuffixListData string
+
+var (
+	defaultPublicSuffixList     *PublicSuffixList
+	defaultPublicSuffixListOnce sync.Once
+)
+
+// ErrNoRegistrableDomain is returned when a host is an IP address or is itself a public suffix.
+var ErrNoRegistrableDomain = errors.New("urlutils: host has no registrable domain")
+
+// PublicSuffixList holds the rules of a public suffix list. It is safe for concurrent use.
+type PublicSuffixList struct {
+	rules map[string]publicSuffixRule
+}
+
+type publicSuffixRule struct {
+	normal    bool
+	wildcard  bool
+	exception bool
+	icann     bool
+}
+
+// Host is a host split into its components.
+type Host struct {
+	// Hostname is the lowercased host without port, brackets, IPv6 zone or trailing dot.
+	Hostname string
+	// Port is the port, or empty if none was given.
+	Port string
+	// IP is the parsed address when the host is an IP literal.
+	IP netip.Addr
+	// PublicSuffix is the effective top-level domain, for example "co.uk". Empty for IP addresses.
+	PublicSuffix string
+	// RegistrableDomain is the public suffix plus one label, for example "example.co.uk".
+	// Empty for IP addresses and for hosts that are themselves public suffixes.
+	RegistrableDomain string
+	// Subdomain is the part of the hostname before the registrable domain, for example "a.b".
+	Subdomain string
+	// ICANN reports whether the public suffix is from the ICANN section of the list rather than the private section.
+	// Suffixes that match no rule are considered non-ICANN.
+	ICANN bool
+}
+
+// IsIP reports whether the host is an IP address literal.
+func (h Host) IsIP() bool {
+	return h.IP.IsValid()
+}
+
+// DefaultPublicSuffixList returns the list parsed from the embedded snapshot.
+func DefaultPublicSuffixList() *PublicSuffixList {
+	defaultPublicSuffixListOnce.Do(func() {
+		list, err := ParsePublicSuffixList(strings.NewReader(publicSuffixListData))
+		if err != nil {
+			panic(fmt.Errorf("urlutils: invalid embedded public suffix list: %w", err))
+		}
+		defaultPublicSuffixList = list
+	})
+	return defaultPublicSuffixList
+}
+
+// LoadPublicSuffixList reads a public suffix list in the publicsuffix.org format from a local file.
+// Use it to supply a list that is more recent than the embedded snapshot.
+func LoadPublicSuffixList(path string) (*PublicSuffixList, error) {
+	file, err := os.Open(path) //nolint:gosec // the path is supplied by the caller on purpose
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParsePublicSuffixList(file)
+}
+
+// ParsePublicSuffixList parses a public suffix list in the publicsuffix.org format.
+func ParsePublicSuffixList(r io.Reader) (*PublicSuffixList, error) {
+	list := &PublicSuffixList{rules: map[string]publicSuffixRule{}}
+	icann := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.Contains(line, "===BEGIN ICANN DOMAINS==="):
+			icann = true
+			continue
+		case strings.Contains(line, "===END ICANN DOMAINS==="):
+			icann = false
+			continue
+		case line == "" || strings.HasPrefix(line, "//"):
+			continue
+		}
+
+		// rules end at the first whitespace
+		if fields := strings.Fields(line); len(fields) > 0 {
+			line = strings.ToLower(fields[0])
+		}
+
+		var key string
+		rule := publicSuffixRule{}
+
+		switch {
+		case strings.HasPrefix(line, "!"):
+			key = line[1:]
+			rule.exception = true
+		case strings.HasPrefix(line, "*."):
+			key = line[2:]
+			rule.wildcard = true
+		default:
+			key = line
+			rule.normal = true
+		}
+
+		// rules are stored in ASCII form so that both Unicode and Punycode hosts can be matched
+		if ascii, err := ToASCII(key); err == nil {
+			key = ascii
+		}
+
+		existing := list.rules[key]
+		existing.normal = existing.normal || rule.normal
+		existing.wildcard = existing.wildcard || rule.wildcard
+		existing.exception = existing.exception || rule.exception
+		existing.icann = existing.icann || icann
+		list.rules[key] = existing
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(list.rules) == 0 {
+		return nil, errors.New("urlutils: public suffix list contains no rules")
+	}
+
+	return list, nil
+}
+
+// PublicSuffix returns the public suffix of a domain and whether it is an ICANN suffix.
+// Domains that match no rule use their last label as the suffix, as required by the list's default "*" rule.
+// Internationalized domains are matched in both Unicode and Punycode form, and the suffix is returned in the
+// form of the input. IP addresses have no public suffix and return an empty string.
+func (l *PublicSuffixList) PublicSuffix(domain string) (suffix string, icann bool) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if domain == "" || isIPAddress(domain) {
+		return "", false
+	}
+
+	labels := strings.Split(domain, ".")
+	count, icann := l.suffixLabelCount(domain)
+	return strings.Join(labels[len(labels)-count:], "."), icann
+}
+
+// suffixLabelCount returns the number of labels of the domain's public suffix.
+func (l *PublicSuffixList) suffixLabelCount(domain string) (int, bool) {
+	if ascii, err := ToASCII(domain); err == nil {
+		domain = ascii
+	}
+
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		candidate := strings.Join(labels[i:], ".")
+
+		if rule, ok := l.rules[candidate]; ok && rule.exception {
+			return len(labels) - i - 1, rule.icann
+		}
+		if rule, ok := l.rules[candidate]; ok && rule.normal {
+			return len(labels) - i, rule.icann
+		}
+		if i+1 < len(labels) {
+			if rule, ok := l.rules[strings.Join(labels[i+1:], ".")]; ok && rule.wildcard {
+				return len(labels) - i, rule.icann
+			}
+		}
+	}
+
+	return 1, false
+}
+
+// RegistrableDomain returns the public suffix of a domain plus one label, for example "example.co.uk"
+// for "a.b.example.co.uk". Returns ErrNoRegistrableDomain if the domain is an IP address or is itself a public suffix.
+func (l *PublicSuffixList) RegistrableDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if isIPAddress(domain) {
+		return "", ErrNoRegistrableDomain
+	}
+	suffix, _ := l.PublicSuffix(domain)
+
+	if len(domain) <= len(suffix) {
+		return "", ErrNoRegistrableDomain
+	}
+
+	rest := strings.TrimSuffix(domain[:len(domain)-len(suffix)], ".")
+	return rest[strings.LastIndexByte(rest, '.')+1:] + "." + suffix, nil
+}
+
+// ParseHost splits a host such as "a.b.example.co.uk:8443" or "[::1]:80" into its components.
+func (l *PublicSuffixList) ParseHost(host string) (Host, error) {
+	hostname, port, err := splitHostPort(host)
+	if err != nil {
+		return Host{}, err
+	}
+
+	result := Host{Hostname: hostname, Port: port}
+
+	if ip, err := netip.ParseAddr(hostname); err == nil {
+		result.IP = ip
+		return result, nil
+	}
+
+	if hostname == "" {
+		return Host{}, fmt.Errorf("urlutils: empty hostname in %q", host)
+	}
+
+	result.PublicSuffix, result.ICANN = l.PublicSuffix(hostname)
+	if registrable, err := l.RegistrableDomain(hostname); err == nil {
+		result.RegistrableDomain = registrable
+		result.Subdomain = strings.TrimSuffix(strings.TrimSuffix(hostname, registrable), ".")
+	}
+
+	return result, nil
+}
+
+// isIPAddress reports whether a hostname, optionally in brackets, is an IPv4 or IPv6 address.
+func isIPAddress(hostname string) bool {
+	_, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(hostname, "["), "]"))
+	return err == nil
+}
+
+// splitHostPort separates the port and removes IPv6 brackets and zones and the trailing dot of a fully qualified name.
+// Zones are removed both in their raw ("%en0") and URL-encoded ("%25en0") forms.
+func splitHostPort(host string) (hostname, port string, err error) {
+	hostname = host
+
+	switch {
+	case strings.HasPrefix(host, "["):
+		end := strings.IndexByte(host, ']')
+		if end < 0 {
+			return "", "", fmt.Errorf("urlutils: missing ']' in host %q", host)
+		}
+		hostname = host[1:end]
+		if rest := host[end+1:]; rest != "" {
+			if !strings.HasPrefix(rest, ":") {
+				return "", "", fmt.Errorf("urlutils: invalid host %q", host)
+			}
+			port = rest[1:]
+		}
+	case strings.Count(host, ":") == 1:
+		hostname, port, _ = strings.Cut(host, ":")
+	}
+
+	if port != "" {
+		if number, err := strconv.Atoi(port); err != nil || number < 0 || number > 65535 {
+			return "", "", fmt.Errorf("urlutils: invalid port %q in host %q", port, host)
+		}
+	}
+
+	if strings.Contains(hostname, ":") {
+		hostname, _, _ = strings.Cut(hostname, "%")
+	}
+
+	return strings.TrimSuffix(strings.ToLower(hostname), "."), port, nil
+}
+
+// ParseHost splits a host into its components using the embedded public suffix list.
+// See PublicSuffixList.ParseHost.
+func ParseHost(host string) (Host, error) {
+	return DefaultPublicSuffixList().ParseHost(host)
+}
+
+// ParseURLHost parses a raw URL and splits its host into components using the embedded public suffix list.
+func ParseURLHost(rawURL string) (Host, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Host{}, err
+	}
+	return ParseHost(u.Host)
+}
+
+// PublicSuffix returns the public suffix of a domain using the embedded public suffix list.
+func PublicSuffix(domain string) (suffix string, icann bool) {
+	return DefaultPublicSuffixList().PublicSuffix(domain)
+}
+
+// RegistrableDomain returns the registrable domain of a domain using the embedded public suffix list.
+func RegistrableDomain(domain string) (string, error) {
+	return DefaultPublicSuffixList().RegistrableDomain(domain)
+}
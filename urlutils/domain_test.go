@@ -0,0 +1,209 @@
+package urlutils_test
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Goldziher/go-utils/urlutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistrableDomain(t *testing.T) {
+	// cases from the publicsuffix.org test data, an empty expectation means no registrable domain
+	testCases := []struct {
+		domain         string
+		expectedOutput string
+	}{
+		{"com", ""},
+		{"example.com", "example.com"},
+		{"b.example.com", "example.com"},
+		{"a.b.example.com", "example.com"},
+		{"uk.com", ""},
+		{"example.uk.com", "example.uk.com"},
+		{"b.example.uk.com", "example.uk.com"},
+		{"test.ac", "test.ac"},
+		{"mm", ""},
+		{"c.mm", ""},
+		{"b.c.mm", "b.c.mm"},
+		{"a.b.c.mm", "b.c.mm"},
+		{"jp", ""},
+		{"test.jp", "test.jp"},
+		{"www.test.jp", "test.jp"},
+		{"ac.jp", ""},
+		{"test.ac.jp", "test.ac.jp"},
+		{"kyoto.jp", ""},
+		{"test.kyoto.jp", "test.kyoto.jp"},
+		{"ide.kyoto.jp", ""},
+		{"b.ide.kyoto.jp", "b.ide.kyoto.jp"},
+		{"c.kobe.jp", ""},
+		{"b.c.kobe.jp", "b.c.kobe.jp"},
+		{"city.kobe.jp", "city.kobe.jp"},
+		{"www.city.kobe.jp", "city.kobe.jp"},
+		{"ck", ""},
+		{"test.ck", ""},
+		{"b.test.ck", "b.test.ck"},
+		{"www.ck", "www.ck"},
+		{"www.www.ck", "www.ck"},
+		{"a.b.example.co.uk", "example.co.uk"},
+		{"Example.COM.", "example.com"},
+		{"example.unknowntld", "example.unknowntld"},
+		{"公司.cn", ""},
+		{"食狮.公司.cn", "食狮.公司.cn"},
+		{"127.0.0.1", ""},
+		{"192.168.0.1", ""},
+		{"::1", ""},
+		{"[2001:db8::1]", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.domain, func(t *testing.T) {
+			actualOutput, err := urlutils.RegistrableDomain(testCase.domain)
+			if testCase.expectedOutput == "" {
+				assert.ErrorIs(t, err, urlutils.ErrNoRegistrableDomain)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func TestPublicSuffix(t *testing.T) {
+	suffix, icann := urlutils.PublicSuffix("a.b.example.co.uk")
+	assert.Equal(t, "co.uk", suffix)
+	assert.True(t, icann)
+
+	suffix, icann = urlutils.PublicSuffix("foo.github.io")
+	assert.Equal(t, "github.io", suffix)
+	assert.False(t, icann)
+
+	suffix, icann = urlutils.PublicSuffix("example.unknowntld")
+	assert.Equal(t, "unknowntld", suffix)
+	assert.False(t, icann)
+
+	suffix, _ = urlutils.PublicSuffix("")
+	assert.Equal(t, "", suffix)
+
+	suffix, icann = urlutils.PublicSuffix("192.168.0.1")
+	assert.Equal(t, "", suffix)
+	assert.False(t, icann)
+}
+
+func TestParseHost(t *testing.T) {
+	testCases := []struct {
+		host         string
+		expectedHost urlutils.Host
+	}{
+		{
+			"a.b.Example.co.uk:8443",
+			urlutils.Host{
+				Hostname:          "a.b.example.co.uk",
+				Port:              "8443",
+				PublicSuffix:      "co.uk",
+				RegistrableDomain: "example.co.uk",
+				Subdomain:         "a.b",
+				ICANN:             true,
+			},
+		},
+		{
+			"example.com.",
+			urlutils.Host{Hostname: "example.com", PublicSuffix: "com", RegistrableDomain: "example.com", ICANN: true},
+		},
+		{
+			"co.uk",
+			urlutils.Host{Hostname: "co.uk", PublicSuffix: "co.uk", ICANN: true},
+		},
+		{
+			"[2001:db8::1]:443",
+			urlutils.Host{Hostname: "2001:db8::1", Port: "443", IP: netip.MustParseAddr("2001:db8::1")},
+		},
+		{
+			"2001:db8::1",
+			urlutils.Host{Hostname: "2001:db8::1", IP: netip.MustParseAddr("2001:db8::1")},
+		},
+		{
+			"[fe80::1%25en0]:8080",
+			urlutils.Host{Hostname: "fe80::1", Port: "8080", IP: netip.MustParseAddr("fe80::1")},
+		},
+		{
+			"[fe80::1%en0]",
+			urlutils.Host{Hostname: "fe80::1", IP: netip.MustParseAddr("fe80::1")},
+		},
+		{
+			"127.0.0.1:80",
+			urlutils.Host{Hostname: "127.0.0.1", Port: "80", IP: netip.MustParseAddr("127.0.0.1")},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.host, func(t *testing.T) {
+			actualHost, err := urlutils.ParseHost(testCase.host)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedHost, actualHost)
+			assert.Equal(t, testCase.expectedHost.IP.IsValid(), actualHost.IsIP())
+		})
+	}
+
+	for _, host := range []string{"", "[::1", "[::1]x", "example.com:99999", "example.com:abc"} {
+		t.Run("invalid "+host, func(t *testing.T) {
+			_, err := urlutils.ParseHost(host)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseURLHost(t *testing.T) {
+	host, err := urlutils.ParseURLHost("https://user@shop.example.co.uk:8443/path")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.co.uk", host.RegistrableDomain)
+	assert.Equal(t, "shop", host.Subdomain)
+	assert.Equal(t, "8443", host.Port)
+
+	host, err = urlutils.ParseURLHost("http://[fe80::1%25en0]:8080/")
+	assert.NoError(t, err)
+	assert.True(t, host.IsIP())
+	assert.Equal(t, "fe80::1", host.Hostname)
+
+	_, err = urlutils.ParseURLHost("://invalid")
+	assert.Error(t, err)
+}
+
+func TestLoadPublicSuffixList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.dat")
+	data := strings.Join([]string{
+		"// ===BEGIN ICANN DOMAINS===",
+		"com",
+		"*.example",
+		"!www.example",
+		"// ===END ICANN DOMAINS===",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"apps.com",
+		"// ===END PRIVATE DOMAINS===",
+	}, "\n")
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	list, err := urlutils.LoadPublicSuffixList(path)
+	assert.NoError(t, err)
+
+	host, err := list.ParseHost("my.tenant.apps.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant.apps.com", host.RegistrableDomain)
+	assert.False(t, host.ICANN)
+
+	registrable, err := list.RegistrableDomain("a.b.foo.example")
+	assert.NoError(t, err)
+	assert.Equal(t, "b.foo.example", registrable)
+
+	registrable, err = list.RegistrableDomain("a.www.example")
+	assert.NoError(t, err)
+	assert.Equal(t, "www.example", registrable)
+
+	_, err = urlutils.LoadPublicSuffixList(filepath.Join(t.TempDir(), "missing.dat"))
+	assert.Error(t, err)
+
+	_, err = urlutils.ParsePublicSuffixList(strings.NewReader("// only comments"))
+	assert.Error(t, err)
+}
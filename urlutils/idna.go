@@ -0,0 +1,417 @@
+package urlutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Punycode parameters from RFC 3492 section 5.
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+	punycodeMaxInt      = 1<<31 - 1
+)
+
+// ACEPrefix is the prefix of IDNA labels encoded with Punycode.
+const ACEPrefix = "xn--"
+
+const (
+	maxLabelLength  = 63
+	maxDomainLength = 253
+)
+
+var errPunycodeOverflow = errors.New("urlutils: punycode overflow")
+
+// PunycodeEncode encodes a string with the Punycode algorithm of RFC 3492, without the "xn--" prefix.
+func PunycodeEncode(input string) (string, error) {
+	if !utf8.ValidString(input) {
+		return "", errors.New("urlutils: punycode input is not valid UTF-8")
+	}
+
+	runes := []rune(input)
+	var output strings.Builder
+
+	for _, r := range runes {
+		if r < 0x80 {
+			output.WriteRune(r)
+		}
+	}
+
+	basicCount := output.Len()
+	handled := basicCount
+	if basicCount > 0 {
+		output.WriteByte('-')
+	}
+
+	n, delta, bias := punycodeInitialN, 0, punycodeInitialBias
+
+	for handled < len(runes) {
+		m := punycodeMaxInt
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+
+		if m-n > (punycodeMaxInt-delta)/(handled+1) {
+			return "", errPunycodeOverflow
+		}
+		delta += (m - n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+				if delta > punycodeMaxInt {
+					return "", errPunycodeOverflow
+				}
+			}
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := punycodeThreshold(k, bias)
+				if q < t {
+					break
+				}
+				output.WriteByte(punycodeDigit(t + (q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			output.WriteByte(punycodeDigit(q))
+
+			bias = punycodeAdapt(delta, handled+1, handled == basicCount)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return output.String(), nil
+}
+
+// PunycodeDecode decodes a Punycode string as described in RFC 3492. The input must not include the "xn--" prefix.
+func PunycodeDecode(input string) (string, error) {
+	var output []rune
+
+	position := 0
+	if separator := strings.LastIndexByte(input, '-'); separator >= 0 {
+		for i := 0; i < separator; i++ {
+			if input[i] >= 0x80 {
+				return "", fmt.Errorf("urlutils: invalid punycode %q", input)
+			}
+			output = append(output, rune(input[i]))
+		}
+		position = separator + 1
+	}
+
+	n, i, bias := punycodeInitialN, 0, punycodeInitialBias
+
+	for position < len(input) {
+		oldI, weight := i, 1
+
+		for k := punycodeBase; ; k += punycodeBase {
+			if position >= len(input) {
+				return "", fmt.Errorf("urlutils: invalid punycode %q", input)
+			}
+
+			digit, ok := punycodeDecodeDigit(input[position])
+			position++
+			if !ok {
+				return "", fmt.Errorf("urlutils: invalid punycode %q", input)
+			}
+			if digit > (punycodeMaxInt-i)/weight {
+				return "", errPunycodeOverflow
+			}
+			i += digit * weight
+
+			t := punycodeThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			if weight > punycodeMaxInt/(punycodeBase-t) {
+				return "", errPunycodeOverflow
+			}
+			weight *= punycodeBase - t
+		}
+
+		length := len(output) + 1
+		bias = punycodeAdapt(i-oldI, length, oldI == 0)
+
+		if i/length > punycodeMaxInt-n {
+			return "", errPunycodeOverflow
+		}
+		n += i / length
+		i %= length
+
+		if n > unicode.MaxRune || (n >= 0xD800 && n <= 0xDFFF) {
+			return "", fmt.Errorf("urlutils: invalid punycode %q", input)
+		}
+
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+
+	return string(output), nil
+}
+
+func punycodeThreshold(k, bias int) int {
+	switch {
+	case k <= bias:
+		return punycodeTMin
+	case k >= bias+punycodeTMax:
+		return punycodeTMax
+	default:
+		return k - bias
+	}
+}
+
+func punycodeAdapt(delta, numPoints int, firstTime bool) int {
+	if firstTime {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+
+	k := 0
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+func punycodeDigit(digit int) byte {
+	if digit < 26 {
+		return byte('a' + digit)
+	}
+	return byte('0' + digit - 26)
+}
+
+func punycodeDecodeDigit(c byte) (int, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c-'0') + 26, true
+	case 'a' <= c && c <= 'z':
+		return int(c - 'a'), true
+	case 'A' <= c && c <= 'Z':
+		return int(c - 'A'), true
+	default:
+		return 0, false
+	}
+}
+
+// IDNAOptions - domain name conversion options.
+type IDNAOptions struct {
+	Strict bool
+}
+
+func parseIDNAOptions(opts ...IDNAOptions) IDNAOptions {
+	options := IDNAOptions{}
+
+	for _, opt := range opts {
+		if opt.Strict {
+			options.Strict = true
+		}
+	}
+
+	return options
+}
+
+// labelSeparators are the characters IDNA treats as label separators in addition to the ASCII full stop.
+var labelSeparators = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// ToASCII converts a domain name to its ASCII form, encoding non-ASCII labels with Punycode,
+// for example "bücher.example" becomes "xn--bcher-kva.example".
+// Labels are lowercased and validated with ValidateLabel. Ideographic full stops are treated as label separators.
+// Unicode normalization (NFC) and the full UTS #46 mapping table are not applied, so the input should already
+// be in normalized form.
+// ToASCII also accepts an options object with the following properties:
+//
+//	IDNAOptions.Strict: apply the STD3 hostname rules, rejecting underscores as in "_dmarc", defaults to false.
+func ToASCII(domain string, opts ...IDNAOptions) (string, error) {
+	return convertDomain(domain, parseIDNAOptions(opts...), func(label string) (string, error) {
+		if isASCII(label) {
+			return label, nil
+		}
+		encoded, err := PunycodeEncode(label)
+		if err != nil {
+			return "", err
+		}
+		return ACEPrefix + encoded, nil
+	})
+}
+
+// ToUnicode converts a domain name to its Unicode form, decoding Punycode labels,
+// for example "xn--bcher-kva.example" becomes "bücher.example". Labels are lowercased and validated with ValidateLabel.
+// ToUnicode also accepts an options object with the same properties as ToASCII.
+func ToUnicode(domain string, opts ...IDNAOptions) (string, error) {
+	return convertDomain(domain, parseIDNAOptions(opts...), func(label string) (string, error) {
+		if !strings.HasPrefix(label, ACEPrefix) {
+			return label, nil
+		}
+		return PunycodeDecode(label[len(ACEPrefix):])
+	})
+}
+
+func convertDomain(domain string, options IDNAOptions, convert func(label string) (string, error)) (string, error) {
+	domain = labelSeparators.Replace(strings.ToLower(domain))
+
+	labels := strings.Split(strings.TrimSuffix(domain, "."), ".")
+	asciiLength := len(labels) - 1
+
+	for i, label := range labels {
+		if err := validateLabel(label, options); err != nil {
+			return "", err
+		}
+
+		converted, err := convert(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = converted
+
+		length, err := asciiLabelLength(label)
+		if err != nil {
+			return "", err
+		}
+		asciiLength += length
+	}
+
+	if asciiLength > maxDomainLength {
+		return "", fmt.Errorf("urlutils: domain %q exceeds %d characters", domain, maxDomainLength)
+	}
+
+	result := strings.Join(labels, ".")
+	if strings.HasSuffix(domain, ".") {
+		result += "."
+	}
+	return result, nil
+}
+
+// asciiLabelLength returns the length of a label in its ASCII form.
+func asciiLabelLength(label string) (int, error) {
+	if isASCII(label) {
+		return len(label), nil
+	}
+	encoded, err := PunycodeEncode(label)
+	if err != nil {
+		return 0, err
+	}
+	return len(ACEPrefix) + len(encoded), nil
+}
+
+// ValidateLabel checks a single domain label in either Unicode or ASCII form. It verifies that the label is
+// not empty, is at most 63 characters in ASCII form, does not start or end with a hyphen, has hyphens in the
+// third and fourth position only as the "xn--" prefix, contains only letters, digits, marks, hyphens and
+// underscores, does not start with a combining mark, and that Punycode labels decode to a valid Unicode label.
+// Underscores are not valid in hostnames under STD3, but are common in DNS names such as "_dmarc.example.com".
+// ValidateLabel also accepts an options object with the following properties:
+//
+//	IDNAOptions.Strict: apply the STD3 hostname rules, rejecting underscores, defaults to false.
+func ValidateLabel(label string, opts ...IDNAOptions) error {
+	return validateLabel(label, parseIDNAOptions(opts...))
+}
+
+func validateLabel(label string, options IDNAOptions) error {
+	if label == "" {
+		return errors.New("urlutils: empty domain label")
+	}
+	if !utf8.ValidString(label) {
+		return fmt.Errorf("urlutils: domain label %q is not valid UTF-8", label)
+	}
+	if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return fmt.Errorf("urlutils: domain label %q starts or ends with a hyphen", label)
+	}
+
+	if isASCII(label) {
+		return validateASCIILabel(label, options)
+	}
+
+	if first, _ := utf8.DecodeRuneInString(label); unicode.Is(unicode.M, first) {
+		return fmt.Errorf("urlutils: domain label %q starts with a combining mark", label)
+	}
+	if runes := []rune(label); len(runes) >= 4 && runes[2] == '-' && runes[3] == '-' {
+		return fmt.Errorf("urlutils: domain label %q has hyphens in the third and fourth position", label)
+	}
+	for _, r := range label {
+		if !isLabelPunctuation(r, options) && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.M, r) {
+			return fmt.Errorf("urlutils: domain label %q contains the disallowed character %q", label, r)
+		}
+	}
+	length, err := asciiLabelLength(label)
+	if err != nil {
+		return err
+	}
+	if length > maxLabelLength {
+		return fmt.Errorf("urlutils: domain label %q exceeds %d characters", label, maxLabelLength)
+	}
+
+	return nil
+}
+
+func validateASCIILabel(label string, options IDNAOptions) error {
+	if len(label) > maxLabelLength {
+		return fmt.Errorf("urlutils: domain label %q exceeds %d characters", label, maxLabelLength)
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		isAlphanumeric := 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+		if !isAlphanumeric && !isLabelPunctuation(rune(c), options) {
+			return fmt.Errorf("urlutils: domain label %q contains the disallowed character %q", label, c)
+		}
+	}
+
+	if len(label) < 4 || label[2:4] != "--" {
+		return nil
+	}
+
+	if !strings.EqualFold(label[:4], ACEPrefix) {
+		return fmt.Errorf("urlutils: domain label %q has hyphens in the third and fourth position", label)
+	}
+
+	decoded, err := PunycodeDecode(label[len(ACEPrefix):])
+	if err != nil {
+		return err
+	}
+	if isASCII(decoded) {
+		return fmt.Errorf("urlutils: punycode label %q decodes to an ASCII label", label)
+	}
+	if err := validateLabel(decoded, options); err != nil {
+		return err
+	}
+	if encoded, err := PunycodeEncode(decoded); err != nil || !strings.EqualFold(ACEPrefix+encoded, label) {
+		return fmt.Errorf("urlutils: punycode label %q is not in canonical form", label)
+	}
+
+	return nil
+}
+
+// isLabelPunctuation returns true for the hyphen, and for the underscore unless options are strict.
+func isLabelPunctuation(r rune, options IDNAOptions) bool {
+	return r == '-' || r == '_' && !options.Strict
+}
+
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package urlutils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Goldziher/go-utils/urlutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPunycode(t *testing.T) {
+	// samples from RFC 3492 section 7.1 and common labels
+	testCases := []struct {
+		decoded string
+		encoded string
+	}{
+		{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+		{"PorquénopuedensimplementehablarenEspañol", "PorqunopuedensimplementehablarenEspaol-fmd56a"},
+		{"3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+		{"安室奈美恵-with-SUPER-MONKEYS", "-with-SUPER-MONKEYS-pc58ag80a8qai00g7n9n"},
+		{"bücher", "bcher-kva"},
+		{"münchen", "mnchen-3ya"},
+		{"☃", "n3h"},
+		{"abc", "abc-"},
+		{"", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.decoded, func(t *testing.T) {
+			encoded, err := urlutils.PunycodeEncode(testCase.decoded)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.encoded, encoded)
+
+			decoded, err := urlutils.PunycodeDecode(testCase.encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.decoded, decoded)
+		})
+	}
+
+	for _, invalid := range []string{"a-b!", "ü-abc", "99999999999", "bcher-kv"} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := urlutils.PunycodeDecode(invalid)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := urlutils.PunycodeEncode("\xff")
+	assert.Error(t, err)
+}
+
+func TestToASCII(t *testing.T) {
+	testCases := []struct {
+		input          string
+		expectedOutput string
+	}{
+		{"Bücher.example", "xn--bcher-kva.example"},
+		{"example.com", "example.com"},
+		{"食狮.公司.cn", "xn--85x722f.xn--55qx5d.cn"},
+		{"münchen。de", "xn--mnchen-3ya.de"},
+		{"bücher.example.", "xn--bcher-kva.example."},
+		{"xn--bcher-kva.example", "xn--bcher-kva.example"},
+		{"my_host.example.com", "my_host.example.com"},
+		{"_dmarc.Bücher.example", "_dmarc.xn--bcher-kva.example"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			actualOutput, err := urlutils.ToASCII(testCase.input)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOutput, actualOutput)
+		})
+	}
+
+	for _, invalid := range []string{"", "a..b", "-abc.com", "abc-.com", "ab--c.com", "a b.com", "xn--abc-.com", strings.Repeat("a", 64) + ".com", strings.Repeat("abcdefghi.", 26) + "com"} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := urlutils.ToASCII(invalid)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := urlutils.ToASCII("my_host.example.com", urlutils.IDNAOptions{Strict: true})
+		assert.Error(t, err)
+
+		actualOutput, err := urlutils.ToASCII("Bücher.example", urlutils.IDNAOptions{Strict: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "xn--bcher-kva.example", actualOutput)
+	})
+}
+
+func TestToUnicode(t *testing.T) {
+	actualOutput, err := urlutils.ToUnicode("XN--BCHER-KVA.example")
+	assert.NoError(t, err)
+	assert.Equal(t, "bücher.example", actualOutput)
+
+	actualOutput, err = urlutils.ToUnicode("xn--85x722f.xn--55qx5d.cn")
+	assert.NoError(t, err)
+	assert.Equal(t, "食狮.公司.cn", actualOutput)
+
+	_, err = urlutils.ToUnicode("xn--abc.example")
+	assert.Error(t, err)
+}
+
+func TestValidateLabel(t *testing.T) {
+	for _, valid := range []string{"example", "bücher", "xn--bcher-kva", "a-b", "123", "食狮", "a_b", "_dmarc"} {
+		assert.NoError(t, urlutils.ValidateLabel(valid), valid)
+	}
+
+	for _, invalid := range []string{"", "-a", "a-", "ab--c", "a b", "́abc", "bü--cher", "xn--abc", "xn--bcher-kvA-", "\xff"} {
+		assert.Error(t, urlutils.ValidateLabel(invalid), invalid)
+	}
+
+	for _, strictInvalid := range []string{"a_b", "_dmarc", "bü_cher"} {
+		assert.Error(t, urlutils.ValidateLabel(strictInvalid, urlutils.IDNAOptions{Strict: true}), strictInvalid)
+	}
+}
+
+func TestIDNIntegration(t *testing.T) {
+	normalized, err := urlutils.Normalize("http://Bücher.example:80/")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://xn--bcher-kva.example/", normalized)
+
+	assert.True(t, urlutils.Equivalent("https://bücher.example/a", "https://xn--bcher-kva.example/a"))
+
+	registrable, err := urlutils.RegistrableDomain("www.xn--85x722f.xn--55qx5d.cn")
+	assert.NoError(t, err)
+	assert.Equal(t, "xn--85x722f.xn--55qx5d.cn", registrable)
+
+	suffix, icann := urlutils.PublicSuffix("食狮.公司.cn")
+	assert.Equal(t, "公司.cn", suffix)
+	assert.True(t, icann)
+}
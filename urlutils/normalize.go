@@ -0,0 +1,354 @@
+package urlutils
+
+import (
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// NormalizeRule selects a single normalization step. Rules are combined with the bitwise OR operator.
+type NormalizeRule uint
+
+const (
+	// NormalizeCase lowercases the scheme and host.
+	NormalizeCase NormalizeRule = 1 << iota
+	// NormalizeDefaultPort removes the port when it is the default port of the scheme.
+	NormalizeDefaultPort
+	// NormalizeDotSegments resolves "." and ".." path segments as described in RFC 3986 section 5.2.4.
+	NormalizeDotSegments
+	// NormalizePercentEncoding uppercases percent-encoding hex digits and decodes encoded unreserved characters.
+	NormalizePercentEncoding
+	// NormalizeSortQuery sorts query parameters by key, keeping the order of repeated keys.
+	NormalizeSortQuery
+	// NormalizeTrackingParams removes query parameters matching NormalizeOptions.TrackingParams.
+	NormalizeTrackingParams
+	// NormalizeFragment removes the fragment.
+	NormalizeFragment
+	// NormalizeTrailingSlash removes trailing slashes from non-root paths and uses "/" for an empty path.
+	NormalizeTrailingSlash
+	// NormalizeIDN converts internationalized host names to their ASCII (Punycode) form using ToASCII.
+	// Hosts that fail IDNA validation are left unchanged.
+	NormalizeIDN
+)
+
+// Rule sets.
+const (
+	// NormalizeSafe contains the rules that never change the resource a URL refers to.
+	NormalizeSafe = NormalizeCase | NormalizeDefaultPort | NormalizeDotSegments | NormalizePercentEncoding |
+		NormalizeIDN
+	// NormalizeDefault contains all rules and is used when no rules are selected.
+	NormalizeDefault = NormalizeSafe | NormalizeSortQuery | NormalizeTrackingParams | NormalizeFragment |
+		NormalizeTrailingSlash
+)
+
+// DefaultTrackingParams are the query parameters removed by NormalizeTrackingParams when no list is configured.
+// Entries are matched case-insensitively using path.Match patterns.
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "gbraid", "wbraid", "msclkid", "yclid", "igshid", "mc_cid", "mc_eid", "_ga", "_gl",
+}
+
+// DefaultPorts maps schemes to the port that NormalizeDefaultPort removes.
+var DefaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// NormalizeOptions - URL normalization options.
+type NormalizeOptions struct {
+	Rules          NormalizeRule
+	TrackingParams []string
+}
+
+func parseNormalizeOptions(opts ...NormalizeOptions) NormalizeOptions {
+	options := NormalizeOptions{
+		Rules:          NormalizeDefault,
+		TrackingParams: DefaultTrackingParams,
+	}
+
+	for _, opt := range opts {
+		if opt.Rules != 0 {
+			options.Rules = opt.Rules
+		}
+		if opt.TrackingParams != nil {
+			options.TrackingParams = opt.TrackingParams
+		}
+	}
+
+	return options
+}
+
+// Normalize parses a raw URL and returns its canonical form.
+// Normalize also accepts an options object with the following properties:
+//
+//	NormalizeOptions.Rules: the normalization rules to apply, defaults to NormalizeDefault (all rules).
+//	NormalizeOptions.TrackingParams: path.Match patterns of query keys to strip, defaults to DefaultTrackingParams.
+func Normalize(rawURL string, opts ...NormalizeOptions) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return NormalizeURL(u, opts...).String(), nil
+}
+
+// NormalizeURL returns a normalized copy of the given URL. See Normalize for the available options.
+func NormalizeURL(u *url.URL, opts ...NormalizeOptions) *url.URL {
+	options := parseNormalizeOptions(opts...)
+	rules := options.Rules
+	result := *u
+
+	if rules&NormalizeCase != 0 {
+		result.Scheme = strings.ToLower(result.Scheme)
+		result.Host = strings.ToLower(result.Host)
+	}
+
+	if rules&NormalizeIDN != 0 {
+		normalizeIDNHost(&result)
+	}
+
+	if rules&NormalizeDefaultPort != 0 {
+		hostname, port := result.Hostname(), result.Port()
+		if port == "" || DefaultPorts[strings.ToLower(result.Scheme)] == port {
+			if strings.Contains(hostname, ":") {
+				hostname = "[" + hostname + "]"
+			}
+			result.Host = hostname
+		}
+	}
+
+	if result.Opaque == "" {
+		normalizePath(&result, rules)
+	}
+
+	if result.RawQuery != "" {
+		result.RawQuery = normalizeQuery(result.RawQuery, options)
+	}
+	if result.RawQuery == "" {
+		result.ForceQuery = false
+	}
+
+	if rules&NormalizeFragment != 0 {
+		result.Fragment = ""
+		result.RawFragment = ""
+	} else if rules&NormalizePercentEncoding != 0 && result.Fragment != "" {
+		setRawFragment(&result, normalizePercentEncoding(result.EscapedFragment()))
+	}
+
+	return &result
+}
+
+// Equivalent reports whether two URLs are equal after normalization with the given options.
+// URLs that cannot be parsed are only equivalent if they are identical.
+func Equivalent(a, b string, opts ...NormalizeOptions) bool {
+	normalizedA, errA := Normalize(a, opts...)
+	normalizedB, errB := Normalize(b, opts...)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return normalizedA == normalizedB
+}
+
+func normalizePath(u *url.URL, rules NormalizeRule) {
+	escaped := u.EscapedPath()
+
+	if rules&NormalizePercentEncoding != 0 {
+		escaped = normalizePercentEncoding(escaped)
+	}
+
+	if rules&NormalizeDotSegments != 0 {
+		escaped = removeDotSegments(escaped)
+	}
+
+	if rules&NormalizeTrailingSlash != 0 {
+		if escaped == "" && u.Host != "" {
+			escaped = "/"
+		}
+		if len(escaped) > 1 {
+			escaped = strings.TrimRight(escaped, "/")
+			if escaped == "" {
+				escaped = "/"
+			}
+		}
+	}
+
+	unescaped, err := url.PathUnescape(escaped)
+	if err != nil {
+		return
+	}
+	u.Path = unescaped
+	u.RawPath = escaped
+}
+
+func normalizeIDNHost(u *url.URL) {
+	hostname := u.Hostname()
+	if isASCII(hostname) {
+		return
+	}
+
+	ascii, err := ToASCII(hostname)
+	if err != nil {
+		return
+	}
+
+	if port := u.Port(); port != "" {
+		u.Host = ascii + ":" + port
+	} else {
+		u.Host = ascii
+	}
+}
+
+func setRawFragment(u *url.URL, escaped string) {
+	unescaped, err := url.PathUnescape(escaped)
+	if err != nil {
+		return
+	}
+	u.Fragment = unescaped
+	u.RawFragment = escaped
+}
+
+// removeDotSegments implements the remove_dot_segments algorithm of RFC 3986 section 5.2.4.
+func removeDotSegments(input string) string {
+	output := make([]string, 0, strings.Count(input, "/"))
+
+	for input != "" {
+		switch {
+		case strings.HasPrefix(input, "../"):
+			input = input[3:]
+		case strings.HasPrefix(input, "./"):
+			input = input[2:]
+		case strings.HasPrefix(input, "/./"):
+			input = input[2:]
+		case input == "/.":
+			input = "/"
+		case strings.HasPrefix(input, "/../"):
+			input = input[3:]
+			if len(output) > 0 {
+				output = output[:len(output)-1]
+			}
+		case input == "/..":
+			input = "/"
+			if len(output) > 0 {
+				output = output[:len(output)-1]
+			}
+		case input == "." || input == "..":
+			input = ""
+		default:
+			end := strings.IndexByte(input[1:], '/')
+			if end < 0 {
+				output = append(output, input)
+				input = ""
+			} else {
+				output = append(output, input[:end+1])
+				input = input[end+1:]
+			}
+		}
+	}
+
+	return strings.Join(output, "")
+}
+
+// normalizePercentEncoding uppercases the hex digits of percent-encoded octets and decodes octets
+// that encode unreserved characters.
+func normalizePercentEncoding(escaped string) string {
+	if !strings.Contains(escaped, "%") {
+		return escaped
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(escaped))
+
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '%' || i+2 >= len(escaped) || !isHex(escaped[i+1]) || !isHex(escaped[i+2]) {
+			builder.WriteByte(escaped[i])
+			continue
+		}
+
+		decoded := unhex(escaped[i+1])<<4 | unhex(escaped[i+2])
+		if isUnreserved(decoded) {
+			builder.WriteByte(decoded)
+		} else {
+			builder.WriteByte('%')
+			builder.WriteString(strings.ToUpper(escaped[i+1 : i+3]))
+		}
+		i += 2
+	}
+
+	return builder.String()
+}
+
+type queryPair struct {
+	key string
+	raw string
+}
+
+// normalizeQuery applies the query related rules to a raw query, keeping the original encoding of each pair.
+func normalizeQuery(rawQuery string, options NormalizeOptions) string {
+	rules := options.Rules
+	pairs := make([]queryPair, 0, strings.Count(rawQuery, "&")+1)
+
+	for _, raw := range strings.Split(rawQuery, "&") {
+		if raw == "" {
+			continue
+		}
+		if rules&NormalizePercentEncoding != 0 {
+			raw = normalizePercentEncoding(raw)
+		}
+
+		rawKey, _, _ := strings.Cut(raw, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+
+		if rules&NormalizeTrackingParams != 0 && matchesAnyPattern(key, options.TrackingParams) {
+			continue
+		}
+		pairs = append(pairs, queryPair{key: key, raw: raw})
+	}
+
+	if rules&NormalizeSortQuery != 0 {
+		slices.SortStableFunc(pairs, func(a, b queryPair) int {
+			return strings.Compare(a.key, b.key)
+		})
+	}
+
+	raws := make([]string, len(pairs))
+	for i, pair := range pairs {
+		raws[i] = pair.raw
+	}
+	return strings.Join(raws, "&")
+}
+
+// matchesAnyPattern reports whether value matches any of the path.Match patterns, ignoring case.
+func matchesAnyPattern(value string, patterns []string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		if matched, err := path.Match(strings.ToLower(pattern), value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
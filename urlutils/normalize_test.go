@@ -0,0 +1,73 @@
+package urlutils_test
+
+import (
+	"testing"
+
+	"github.com/Goldziher/go-utils/urlutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		input          string
+		rules          urlutils.NormalizeRule
+		expectedOutput string
+	}{
+		{"HTTPS://Example.COM:443/a/./b/../c?b=2&a=1#frag", 0, "https://example.com/a/c?a=1&b=2"},
+		{"http://example.com:80", 0, "http://example.com/"},
+		{"http://example.com:8080/path/", 0, "http://example.com:8080/path"},
+		{"http://[::1]:80/", 0, "http://[::1]/"},
+		{"http://example.com/%7euser/%2fx%3a", 0, "http://example.com/~user/%2Fx%3A"},
+		{"http://example.com/?utm_source=x&id=1&UTM_Medium=y&fbclid=z", 0, "http://example.com/?id=1"},
+		{"http://example.com/?utm_source=x", 0, "http://example.com/"},
+		{"http://example.com/?b=2&a=3&b=1", 0, "http://example.com/?a=3&b=2&b=1"},
+		{"http://example.com/a/b/../../../c", 0, "http://example.com/c"},
+		{"http://example.com/#%7efrag", urlutils.NormalizeSafe, "http://example.com/#~frag"},
+		{"HTTP://Example.com:80/a/?z=1&utm_source=x#f", urlutils.NormalizeSafe, "http://example.com/a/?z=1&utm_source=x#f"},
+		{"http://example.com/a/?z=1&y=2", urlutils.NormalizeSortQuery, "http://example.com/a/?y=2&z=1"},
+		{"mailto:User@Example.com", 0, "mailto:User@Example.com"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			actualOutput, err := urlutils.Normalize(testCase.input, urlutils.NormalizeOptions{Rules: testCase.rules})
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOutput, actualOutput)
+		})
+	}
+
+	t.Run("Test custom tracking params", func(t *testing.T) {
+		actualOutput, err := urlutils.Normalize("http://example.com/?ref=x&session_id=1&utm_source=y", urlutils.NormalizeOptions{
+			TrackingParams: []string{"ref", "session_*"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "http://example.com/?utm_source=y", actualOutput)
+	})
+
+	t.Run("Test invalid URL", func(t *testing.T) {
+		_, err := urlutils.Normalize("://invalid")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test NormalizeURL does not modify its input", func(t *testing.T) {
+		u := urlutils.MustParse("HTTP://Example.com/a/../b")
+		assert.Equal(t, "http://example.com/b", urlutils.NormalizeURL(u).String())
+		assert.Equal(t, "http://Example.com/a/../b", u.String())
+	})
+}
+
+func TestEquivalent(t *testing.T) {
+	assert.True(t, urlutils.Equivalent(
+		"https://Example.com:443/docs/?b=2&a=1&utm_campaign=x#intro",
+		"https://example.com/docs?a=1&b=2",
+	))
+	assert.True(t, urlutils.Equivalent("http://example.com/%7Euser", "http://example.com/~user"))
+	assert.False(t, urlutils.Equivalent("http://example.com/a", "http://example.com/b"))
+	assert.False(t, urlutils.Equivalent(
+		"http://example.com/?b=2&a=1",
+		"http://example.com/?a=1&b=2",
+		urlutils.NormalizeOptions{Rules: urlutils.NormalizeSafe},
+	))
+	assert.True(t, urlutils.Equivalent("://invalid", "://invalid"))
+	assert.False(t, urlutils.Equivalent("://invalid", "http://example.com"))
+}
@@ -0,0 +1,134 @@
+package urlutils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactMask is the replacement used by Redact when no mask is configured.
+const DefaultRedactMask = "REDACTED"
+
+// DefaultSensitiveParams are the query parameters masked by Redact when no list is configured.
+// Entries are matched case-insensitively using path.Match patterns.
+var DefaultSensitiveParams = []string{
+	"token", "*_token", "*-token", "key", "*_key", "*-key", "apikey", "signature", "sig", "x-amz-signature",
+	"password", "passwd", "pwd", "secret", "*_secret", "auth", "authorization", "session", "sessionid",
+}
+
+// RedactOptions - URL redaction options.
+type RedactOptions struct {
+	Mask            string
+	SensitiveParams []string
+	PathPatterns    []*regexp.Regexp
+}
+
+func parseRedactOptions(opts ...RedactOptions) RedactOptions {
+	options := RedactOptions{
+		Mask:            DefaultRedactMask,
+		SensitiveParams: DefaultSensitiveParams,
+	}
+
+	for _, opt := range opts {
+		if opt.Mask != "" {
+			options.Mask = opt.Mask
+		}
+		if opt.SensitiveParams != nil {
+			options.SensitiveParams = opt.SensitiveParams
+		}
+		if opt.PathPatterns != nil {
+			options.PathPatterns = opt.PathPatterns
+		}
+	}
+
+	return options
+}
+
+// Redact masks credentials and secrets in a URL so that it can be logged safely. The result remains a parseable URL.
+// The userinfo password is always masked, as are the values of sensitive query parameters, including those in a
+// query-like fragment such as "#access_token=...". Path segments matching one of the path patterns are replaced
+// entirely. If the URL cannot be parsed, the mask itself is returned so that nothing leaks.
+// Redact also accepts an options object with the following properties:
+//
+//	RedactOptions.Mask: the replacement for masked values, defaults to "REDACTED".
+//	RedactOptions.SensitiveParams: path.Match patterns of query keys to mask, defaults to DefaultSensitiveParams.
+//	RedactOptions.PathPatterns: regular expressions matched against each unescaped path segment, defaults to none.
+func Redact(rawURL string, opts ...RedactOptions) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return parseRedactOptions(opts...).Mask
+	}
+	return RedactURL(u, opts...).String()
+}
+
+// RedactURL returns a redacted copy of the given URL. See Redact for the available options.
+func RedactURL(u *url.URL, opts ...RedactOptions) *url.URL {
+	options := parseRedactOptions(opts...)
+	result := *u
+
+	if result.User != nil {
+		if _, hasPassword := result.User.Password(); hasPassword {
+			result.User = url.UserPassword(result.User.Username(), options.Mask)
+		}
+	}
+
+	if len(options.PathPatterns) > 0 && result.Opaque == "" {
+		redactPath(&result, options)
+	}
+
+	if result.RawQuery != "" {
+		result.RawQuery = redactQuery(result.RawQuery, options)
+	}
+
+	if strings.Contains(result.Fragment, "=") {
+		setRawFragment(&result, redactQuery(result.EscapedFragment(), options))
+	}
+
+	return &result
+}
+
+func redactPath(u *url.URL, options RedactOptions) {
+	segments := strings.Split(u.EscapedPath(), "/")
+
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			unescaped = segment
+		}
+		for _, pattern := range options.PathPatterns {
+			if segment != "" && pattern.MatchString(unescaped) {
+				segments[i] = url.PathEscape(options.Mask)
+				break
+			}
+		}
+	}
+
+	escaped := strings.Join(segments, "/")
+	if unescaped, err := url.PathUnescape(escaped); err == nil {
+		u.Path = unescaped
+		u.RawPath = escaped
+	}
+}
+
+// redactQuery masks the values of sensitive parameters, keeping the order and encoding of all other pairs.
+func redactQuery(rawQuery string, options RedactOptions) string {
+	pairs := strings.Split(rawQuery, "&")
+
+	for i, pair := range pairs {
+		rawKey, _, hasValue := strings.Cut(pair, "=")
+		if !hasValue {
+			continue
+		}
+
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+
+		if matchesAnyPattern(key, options.SensitiveParams) {
+			pairs[i] = rawKey + "=" + url.QueryEscape(options.Mask)
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,139 @@
+package urlutils_test
+
+import (
+	"crypto/sha512"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Goldziher/go-utils/dateutils"
+	"github.com/Goldziher/go-utils/urlutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	signingKey = []byte("secret")
+	signedAt   = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func clockAt(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestSignURL(t *testing.T) {
+	signed, err := urlutils.SignURL(
+		"https://cdn.example.com/files/report.pdf?b=2&a=1",
+		signingKey,
+		time.Hour,
+		urlutils.SignOptions{Now: clockAt(signedAt)},
+	)
+	assert.NoError(t, err)
+
+	u := urlutils.MustParse(signed)
+	assert.Equal(t, "1704114000", u.Query().Get("expires"))
+	assert.NotEmpty(t, u.Query().Get("signature"))
+
+	t.Run("Test valid until expiry", func(t *testing.T) {
+		assert.NoError(t, urlutils.VerifySignedURL(signed, signingKey, urlutils.SignOptions{Now: clockAt(signedAt)}))
+		assert.NoError(t, urlutils.VerifySignedURL(signed, signingKey, urlutils.SignOptions{
+			Now: clockAt(signedAt.Add(time.Hour)),
+		}))
+	})
+
+	t.Run("Test expired", func(t *testing.T) {
+		err := urlutils.VerifySignedURL(signed, signingKey, urlutils.SignOptions{
+			Now: clockAt(signedAt.Add(time.Hour + time.Second)),
+		})
+		assert.ErrorIs(t, err, urlutils.ErrURLExpired)
+	})
+
+	t.Run("Test fake clock", func(t *testing.T) {
+		clock := dateutils.NewFakeClock(signedAt)
+		options := urlutils.SignOptions{Now: clock.Now}
+
+		assert.NoError(t, urlutils.VerifySignedURL(signed, signingKey, options))
+		clock.Advance(time.Hour + time.Second)
+		assert.ErrorIs(t, urlutils.VerifySignedURL(signed, signingKey, options), urlutils.ErrURLExpired)
+	})
+
+	t.Run("Test query order and host do not matter", func(t *testing.T) {
+		reordered := *u
+		query := u.Query()
+		reordered.RawQuery = "signature=" + url.QueryEscape(query.Get("signature")) +
+			"&a=1&expires=" + query.Get("expires") + "&b=2"
+		reordered.Host = "origin.internal:8080"
+		assert.NoError(t, urlutils.VerifySignedURL(reordered.String(), signingKey, urlutils.SignOptions{
+			Now: clockAt(signedAt),
+		}))
+	})
+
+	t.Run("Test tampered", func(t *testing.T) {
+		for _, tampered := range []func(u url.URL) string{
+			func(u url.URL) string { u.Path = "/files/other.pdf"; return u.String() },
+			func(u url.URL) string { q := u.Query(); q.Set("a", "2"); u.RawQuery = q.Encode(); return u.String() },
+			func(u url.URL) string {
+				q := u.Query()
+				q.Set("expires", "1804114000")
+				u.RawQuery = q.Encode()
+				return u.String()
+			},
+			func(u url.URL) string {
+				q := u.Query()
+				q.Set("signature", "AAAA")
+				u.RawQuery = q.Encode()
+				return u.String()
+			},
+		} {
+			err := urlutils.VerifySignedURL(tampered(*u), signingKey, urlutils.SignOptions{
+				Now: clockAt(signedAt.Add(2 * time.Hour)),
+			})
+			assert.ErrorIs(t, err, urlutils.ErrURLTampered)
+		}
+
+		err := urlutils.VerifySignedURL(signed, []byte("other"), urlutils.SignOptions{Now: clockAt(signedAt)})
+		assert.ErrorIs(t, err, urlutils.ErrURLTampered)
+	})
+
+	t.Run("Test malformed", func(t *testing.T) {
+		for _, malformed := range []string{
+			"://invalid",
+			"https://cdn.example.com/files/report.pdf",
+			"https://cdn.example.com/files/report.pdf?expires=abc&signature=AAAA",
+			"https://cdn.example.com/files/report.pdf?expires=1&signature=!!",
+			"https://cdn.example.com/files/report.pdf?expires=1&expires=2&signature=AAAA",
+			"https://cdn.example.com/files/report.pdf?expires=1&signature=AAAA&%zz",
+		} {
+			assert.ErrorIs(t, urlutils.VerifySignedURL(malformed, signingKey), urlutils.ErrURLMalformed, malformed)
+		}
+	})
+}
+
+func TestSignURLOptions(t *testing.T) {
+	options := urlutils.SignOptions{
+		Hash:           sha512.New,
+		ExpiresParam:   "X-Expires",
+		SignatureParam: "X-Signature",
+		Now:            clockAt(signedAt),
+	}
+
+	signed, err := urlutils.SignURL("/download/a/../b?X-Signature=old", signingKey, time.Minute, options)
+	assert.NoError(t, err)
+
+	query := urlutils.MustParse(signed).Query()
+	assert.Len(t, query["X-Signature"], 1)
+	assert.NotEqual(t, "old", query.Get("X-Signature"))
+	assert.Equal(t, "1704110460", query.Get("X-Expires"))
+
+	assert.NoError(t, urlutils.VerifySignedURL(signed, signingKey, options))
+	assert.ErrorIs(t, urlutils.VerifySignedURL(signed, signingKey, urlutils.SignOptions{
+		Now: clockAt(signedAt),
+	}), urlutils.ErrURLMalformed)
+
+	_, err = urlutils.SignURL("/a", nil, time.Minute)
+	assert.Error(t, err)
+	_, err = urlutils.SignURL("/a", signingKey, 0)
+	assert.Error(t, err)
+	_, err = urlutils.SignURL("://invalid", signingKey, time.Minute)
+	assert.Error(t, err)
+	assert.Error(t, urlutils.VerifySignedURL("/a", nil))
+}
@@ -0,0 +1,629 @@
+package urlutils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Template is a parsed RFC 6570 URI Template supporting expansion levels 1 through 4 and matching.
+// A Template is immutable and safe for concurrent use.
+type Template struct {
+	raw     string
+	parts   []templatePart
+	matcher *regexp.Regexp
+}
+
+// templatePart is either a literal or an expression.
+type templatePart struct {
+	literal  string
+	operator *templateOperator
+	varspecs []templateVarspec
+}
+
+type templateVarspec struct {
+	name    string
+	prefix  int
+	explode bool
+}
+
+// templateOperator holds the expansion behavior of an expression operator, see RFC 6570 appendix A.
+type templateOperator struct {
+	first         string
+	separator     string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+	matchPattern  string
+}
+
+var templateOperators = map[byte]*templateOperator{
+	0:   {first: "", separator: ",", matchPattern: `[^/?#&;]*?`},
+	'+': {first: "", separator: ",", allowReserved: true, matchPattern: `[^?#]*?`},
+	'#': {first: "#", separator: ",", allowReserved: true, matchPattern: `.*?`},
+	'.': {first: ".", separator: ".", matchPattern: `[^/?#;]*?`},
+	'/': {first: "/", separator: "/", matchPattern: `[^?#;]*?`},
+	';': {first: ";", separator: ";", named: true, matchPattern: `[^/?#]*?`},
+	'?': {first: "?", separator: "&", named: true, ifEmpty: "=", matchPattern: `[^#]*?`},
+	'&': {first: "&", separator: "&", named: true, ifEmpty: "=", matchPattern: `[^#]*?`},
+}
+
+// ParseTemplate parses an RFC 6570 URI Template such as "/repos/{owner}/{repo}/issues{?state,labels*}".
+func ParseTemplate(template string) (*Template, error) {
+	result := &Template{raw: template}
+
+	rest := template
+	for rest != "" {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			result.parts = append(result.parts, templatePart{literal: rest})
+			break
+		}
+		if rest[start] == '}' {
+			return nil, fmt.Errorf("urlutils: unexpected '}' in template %q", template)
+		}
+		if start > 0 {
+			result.parts = append(result.parts, templatePart{literal: rest[:start]})
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("urlutils: unterminated expression in template %q", template)
+		}
+
+		part, err := parseTemplateExpression(rest[start+1 : start+end])
+		if err != nil {
+			return nil, fmt.Errorf("urlutils: invalid template %q: %w", template, err)
+		}
+		result.parts = append(result.parts, part)
+		rest = rest[start+end+1:]
+	}
+
+	matcher, err := regexp.Compile(result.matchPattern())
+	if err != nil {
+		return nil, fmt.Errorf("urlutils: invalid template %q: %w", template, err)
+	}
+	result.matcher = matcher
+
+	return result, nil
+}
+
+// MustParseTemplate parses a URI Template and panics if parsing fails.
+// Use this when you're certain the template is valid.
+func MustParseTemplate(template string) *Template {
+	t, err := ParseTemplate(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// ExpandTemplate parses and expands a URI Template in one step. See Template.Expand.
+func ExpandTemplate(template string, variables any, structTags ...string) (string, error) {
+	t, err := ParseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	return t.Expand(variables, structTags...)
+}
+
+func parseTemplateExpression(expression string) (templatePart, error) {
+	if expression == "" {
+		return templatePart{}, errors.New("empty expression")
+	}
+
+	operatorKey := byte(0)
+	if _, isOperator := templateOperators[expression[0]]; isOperator {
+		operatorKey = expression[0]
+		expression = expression[1:]
+	} else if strings.ContainsRune("=,!@|", rune(expression[0])) {
+		return templatePart{}, fmt.Errorf("reserved operator %q", expression[0])
+	}
+
+	part := templatePart{operator: templateOperators[operatorKey]}
+
+	for _, spec := range strings.Split(expression, ",") {
+		varspec := templateVarspec{name: spec}
+
+		if name, isExploded := strings.CutSuffix(spec, "*"); isExploded {
+			varspec.name = name
+			varspec.explode = true
+		} else if name, prefix, hasPrefix := strings.Cut(spec, ":"); hasPrefix {
+			length, err := strconv.Atoi(prefix)
+			if err != nil || length < 1 || length > 9999 {
+				return templatePart{}, fmt.Errorf("invalid prefix modifier in %q", spec)
+			}
+			varspec.name = name
+			varspec.prefix = length
+		}
+
+		if !isValidVarname(varspec.name) {
+			return templatePart{}, fmt.Errorf("invalid variable name %q", varspec.name)
+		}
+		part.varspecs = append(part.varspecs, varspec)
+	}
+
+	return part, nil
+}
+
+func isValidVarname(name string) bool {
+	if name == "" || name[0] == '.' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '%':
+			if i+2 >= len(name) || !isHex(name[i+1]) || !isHex(name[i+2]) {
+				return false
+			}
+			i += 2
+		case c == '_' || c == '.' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the template as it was parsed.
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Variables returns the names of the variables used in the template, in order of first appearance.
+func (t *Template) Variables() []string {
+	var names []string
+	for _, part := range t.parts {
+		for _, varspec := range part.varspecs {
+			if !slices.Contains(names, varspec.name) {
+				names = append(names, varspec.name)
+			}
+		}
+	}
+	return names
+}
+
+// Expand expands the template with the given variables, which can be a map with string keys or a struct.
+// Takes struct tag names as optional parameters, resolved the same way as in QueryStringifyStruct, so fields
+// tagged with omitempty or omitzero are undefined when empty.
+//
+// Slices and arrays are expanded as lists, maps and structs as associative arrays (map keys are sorted), and all
+// other values are converted like query values, so QueryMarshaler, encoding.TextMarshaler and time.Time are supported.
+// Nil values, empty lists and empty associative arrays are undefined and skipped.
+// An error is returned if a prefix modifier is applied to a list or associative array.
+func (t *Template) Expand(variables any, structTags ...string) (string, error) {
+	lookup := templateLookup(variables, structTags)
+
+	var builder strings.Builder
+	for _, part := range t.parts {
+		if part.operator == nil {
+			builder.WriteString(encodeTemplateValue(part.literal, true))
+			continue
+		}
+		if err := part.expand(&builder, lookup, structTags); err != nil {
+			return "", err
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// templateValue is a variable value classified for expansion.
+type templateValue struct {
+	scalar string
+	list   []string
+	keys   []string
+	values []string
+	kind   int
+}
+
+const (
+	templateUndefined = iota
+	templateScalar
+	templateList
+	templateAssociative
+)
+
+func (part templatePart) expand(builder *strings.Builder, lookup map[string]any, structTags []string) error {
+	operator := part.operator
+	first := true
+
+	for _, varspec := range part.varspecs {
+		value, err := classifyTemplateValue(lookup[varspec.name], structTags)
+		if err != nil {
+			return err
+		}
+		if value.kind == templateUndefined {
+			continue
+		}
+		if varspec.prefix > 0 && value.kind != templateScalar {
+			return fmt.Errorf("urlutils: prefix modifier applied to composite variable %q", varspec.name)
+		}
+
+		if first {
+			builder.WriteString(operator.first)
+			first = false
+		} else {
+			builder.WriteString(operator.separator)
+		}
+
+		switch {
+		case value.kind == templateScalar:
+			scalar := value.scalar
+			if varspec.prefix > 0 && utf8.RuneCountInString(scalar) > varspec.prefix {
+				scalar = string([]rune(scalar)[:varspec.prefix])
+			}
+			operator.writeName(builder, varspec.name, scalar == "")
+			builder.WriteString(encodeTemplateValue(scalar, operator.allowReserved))
+		case !varspec.explode:
+			operator.writeName(builder, varspec.name, false)
+			builder.WriteString(strings.Join(operator.encodeComposite(value, false), ","))
+		case value.kind == templateList && operator.named:
+			for i, item := range value.list {
+				if i > 0 {
+					builder.WriteString(operator.separator)
+				}
+				operator.writeName(builder, varspec.name, item == "")
+				builder.WriteString(encodeTemplateValue(item, operator.allowReserved))
+			}
+		default:
+			builder.WriteString(strings.Join(operator.encodeComposite(value, true), operator.separator))
+		}
+	}
+
+	return nil
+}
+
+func (o *templateOperator) writeName(builder *strings.Builder, name string, isEmpty bool) {
+	if !o.named {
+		return
+	}
+	builder.WriteString(name)
+	if isEmpty {
+		builder.WriteString(o.ifEmpty)
+	} else {
+		builder.WriteString("=")
+	}
+}
+
+// encodeComposite encodes the items of a list, or the key and value pairs of an associative array.
+// Exploded pairs are joined with "=", otherwise keys and values are alternated as separate items.
+func (o *templateOperator) encodeComposite(value templateValue, explode bool) []string {
+	if value.kind == templateList {
+		result := make([]string, len(value.list))
+		for i, item := range value.list {
+			result[i] = encodeTemplateValue(item, o.allowReserved)
+		}
+		return result
+	}
+
+	result := make([]string, 0, len(value.keys)*2)
+	for i, key := range value.keys {
+		encodedKey := encodeTemplateValue(key, o.allowReserved)
+		encodedValue := encodeTemplateValue(value.values[i], o.allowReserved)
+		switch {
+		case !explode:
+			result = append(result, encodedKey, encodedValue)
+		case o.named && encodedValue == "":
+			result = append(result, encodedKey+o.ifEmpty)
+		default:
+			result = append(result, encodedKey+"="+encodedValue)
+		}
+	}
+	return result
+}
+
+// templateLookup resolves the variables of a map or struct into a map keyed by variable name.
+func templateLookup(variables any, structTags []string) map[string]any {
+	lookup := map[string]any{}
+
+	value := reflect.ValueOf(variables)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			lookup[fmt.Sprint(key.Interface())] = value.MapIndex(key).Interface()
+		}
+	case reflect.Struct:
+		for _, field := range reflect.VisibleFields(value.Type()) {
+			if !field.IsExported() || (field.Anonymous && field.Type.Kind() == reflect.Struct) {
+				continue
+			}
+			tag := parseFieldTag(field, structTags)
+			fieldValue := value.FieldByIndex(field.Index)
+			if tag.name != "-" && !tag.omit(fieldValue) {
+				lookup[tag.name] = fieldValue.Interface()
+			}
+		}
+	}
+
+	return lookup
+}
+
+func classifyTemplateValue(variable any, structTags []string) (templateValue, error) {
+	value := reflect.ValueOf(variable)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return templateValue{}, nil
+		}
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return templateValue{}, nil
+	}
+
+	options := parseQueryOptions()
+
+	switch {
+	case isQuerySequence(value):
+		if value.Len() == 0 {
+			return templateValue{}, nil
+		}
+		result := templateValue{kind: templateList, list: make([]string, value.Len())}
+		for i := range result.list {
+			encoded, err := encodeQueryLeaf(value.Index(i), options)
+			if err != nil {
+				return templateValue{}, err
+			}
+			result.list[i] = encoded
+		}
+		return result, nil
+	case value.Kind() == reflect.Map || (value.Kind() == reflect.Struct && !isQueryLeaf(value.Type())):
+		entries := templateLookup(value.Interface(), structTags)
+		if len(entries) == 0 {
+			return templateValue{}, nil
+		}
+		result := templateValue{kind: templateAssociative}
+		for key := range entries {
+			result.keys = append(result.keys, key)
+		}
+		if value.Kind() == reflect.Map {
+			slices.Sort(result.keys)
+		} else {
+			result.keys = structFieldOrder(value.Type(), structTags, entries)
+		}
+		for _, key := range result.keys {
+			encoded, err := encodeQueryLeaf(reflect.ValueOf(entries[key]), options)
+			if err != nil {
+				return templateValue{}, err
+			}
+			result.values = append(result.values, encoded)
+		}
+		return result, nil
+	default:
+		encoded, err := encodeQueryLeaf(value, options)
+		if err != nil {
+			return templateValue{}, err
+		}
+		return templateValue{kind: templateScalar, scalar: encoded}, nil
+	}
+}
+
+// structFieldOrder returns the resolved field names of a struct in declaration order.
+func structFieldOrder(structType reflect.Type, structTags []string, entries map[string]any) []string {
+	result := make([]string, 0, len(entries))
+	for _, field := range reflect.VisibleFields(structType) {
+		name := parseFieldTag(field, structTags).name
+		if _, ok := entries[name]; ok && !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+const upperHex = "0123456789ABCDEF"
+
+// encodeTemplateValue percent-encodes a value, keeping unreserved characters and, if allowReserved is set,
+// reserved characters and existing percent-encoded triplets.
+func encodeTemplateValue(value string, allowReserved bool) string {
+	var builder strings.Builder
+	builder.Grow(len(value))
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case isUnreserved(c):
+			builder.WriteByte(c)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			builder.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(value) && isHex(value[i+1]) && isHex(value[i+2]):
+			builder.WriteString(value[i : i+3])
+			i += 2
+		default:
+			builder.WriteByte('%')
+			builder.WriteByte(upperHex[c>>4])
+			builder.WriteByte(upperHex[c&15])
+		}
+	}
+
+	return builder.String()
+}
+
+// matchPattern builds the regular expression used to match URIs against the template.
+func (t *Template) matchPattern() string {
+	var builder strings.Builder
+	builder.WriteString("^")
+
+	for _, part := range t.parts {
+		if part.operator == nil {
+			builder.WriteString(regexp.QuoteMeta(encodeTemplateValue(part.literal, true)))
+			continue
+		}
+		builder.WriteString("(?:" + regexp.QuoteMeta(part.operator.first) + "(" + part.operator.matchPattern + "))?")
+	}
+
+	builder.WriteString("$")
+	return builder.String()
+}
+
+// Match matches a URI against the template and extracts the variables.
+// Scalar variables are returned as strings, exploded lists as []string and exploded associative arrays as
+// map[string]string. Non-exploded lists cannot be told apart from scalars and are returned as the decoded string.
+// Variables that are absent from the URI are omitted from the result.
+// Returns false if the URI does not match the template.
+func (t *Template) Match(uri string) (map[string]any, bool) {
+	submatches := t.matcher.FindStringSubmatchIndex(uri)
+	if submatches == nil {
+		return nil, false
+	}
+
+	result := map[string]any{}
+	group := 1
+
+	for _, part := range t.parts {
+		if part.operator == nil {
+			continue
+		}
+		start, end := submatches[group*2], submatches[group*2+1]
+		group++
+		if start < 0 {
+			continue
+		}
+		if !part.match(uri[start:end], result) {
+			return nil, false
+		}
+	}
+
+	return result, true
+}
+
+func (part templatePart) match(content string, result map[string]any) bool {
+	if content == "" && !part.operator.named {
+		return len(part.varspecs) > 0 && part.assignEmpty(result)
+	}
+
+	pieces := strings.Split(content, part.operator.separator)
+	if part.operator.named {
+		return part.matchNamed(pieces, result)
+	}
+	return part.matchPositional(pieces, result)
+}
+
+func (part templatePart) assignEmpty(result map[string]any) bool {
+	if !part.varspecs[0].explode {
+		result[part.varspecs[0].name] = ""
+	}
+	return true
+}
+
+func (part templatePart) matchNamed(pieces []string, result map[string]any) bool {
+	var exploded *templateVarspec
+	for i := range part.varspecs {
+		if part.varspecs[i].explode {
+			exploded = &part.varspecs[i]
+		}
+	}
+
+	for _, piece := range pieces {
+		rawName, rawValue, _ := strings.Cut(piece, "=")
+		name, nameErr := url.PathUnescape(rawName)
+		value, valueErr := url.PathUnescape(rawValue)
+		if nameErr != nil || valueErr != nil {
+			return false
+		}
+
+		index := slices.IndexFunc(part.varspecs, func(varspec templateVarspec) bool { return varspec.name == name })
+		switch {
+		case index >= 0 && part.varspecs[index].explode:
+			list, _ := result[name].([]string)
+			result[name] = append(list, value)
+		case index >= 0:
+			result[name] = value
+		case exploded != nil:
+			entries, _ := result[exploded.name].(map[string]string)
+			if entries == nil {
+				entries = map[string]string{}
+				result[exploded.name] = entries
+			}
+			entries[name] = value
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func (part templatePart) matchPositional(pieces []string, result map[string]any) bool {
+	varspecs := part.varspecs
+	separator := part.operator.separator
+
+	for i, varspec := range varspecs {
+		if len(pieces) == 0 {
+			break
+		}
+
+		remaining := len(varspecs) - i - 1
+		take := 1
+		if varspec.explode || remaining == 0 {
+			take = max(len(pieces)-remaining, 1)
+		}
+
+		taken := pieces[:take]
+		pieces = pieces[take:]
+
+		if !varspec.explode {
+			value, err := url.PathUnescape(strings.Join(taken, separator))
+			if err != nil {
+				return false
+			}
+			result[varspec.name] = value
+			continue
+		}
+
+		decoded, ok := decodeTemplatePieces(taken)
+		if !ok {
+			return false
+		}
+		result[varspec.name] = decoded
+	}
+
+	return true
+}
+
+// decodeTemplatePieces decodes exploded pieces into map[string]string if they are all key=value pairs,
+// and into []string otherwise.
+func decodeTemplatePieces(pieces []string) (any, bool) {
+	isAssociative := true
+	for _, piece := range pieces {
+		if !strings.Contains(piece, "=") {
+			isAssociative = false
+			break
+		}
+	}
+
+	if isAssociative {
+		entries := make(map[string]string, len(pieces))
+		for _, piece := range pieces {
+			rawKey, rawValue, _ := strings.Cut(piece, "=")
+			key, keyErr := url.PathUnescape(rawKey)
+			value, valueErr := url.PathUnescape(rawValue)
+			if keyErr != nil || valueErr != nil {
+				return nil, false
+			}
+			entries[key] = value
+		}
+		return entries, true
+	}
+
+	list := make([]string, len(pieces))
+	for i, piece := range pieces {
+		value, err := url.PathUnescape(piece)
+		if err != nil {
+			return nil, false
+		}
+		list[i] = value
+	}
+	return list, true
+}
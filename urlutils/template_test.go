@@ -0,0 +1,255 @@
+package urlutils_test
+
+import (
+	"testing"
+
+	"github.com/Goldziher/go-utils/urlutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6570Variables are the example variables of RFC 6570 section 3.2.
+var rfc6570Variables = map[string]any{
+	"count":      []string{"one", "two", "three"},
+	"dom":        []string{"example", "com"},
+	"dub":        "me/too",
+	"hello":      "Hello World!",
+	"half":       "50%",
+	"var":        "value",
+	"who":        "fred",
+	"base":       "http://example.com/home/",
+	"path":       "/foo/bar",
+	"list":       []string{"red", "green", "blue"},
+	"keys":       map[string]string{"semi": ";", "dot": ".", "comma": ","},
+	"v":          "6",
+	"x":          "1024",
+	"y":          "768",
+	"empty":      "",
+	"empty_keys": map[string]string{},
+	"undef":      nil,
+}
+
+func TestTemplateExpand(t *testing.T) {
+	testCases := []struct {
+		template       string
+		expectedOutput string
+	}{
+		// level 1
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{half}", "50%25"},
+		{"O{empty}X", "OX"},
+		{"O{undef}X", "OX"},
+		// level 2
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"{#var}", "#value"},
+		{"{#hello}", "#Hello%20World!"},
+		{"{+half}", "50%25"},
+		{"{base}index", "http%3A%2F%2Fexample.com%2Fhome%2Findex"},
+		{"{+base}index", "http://example.com/home/index"},
+		// level 3
+		{"map?{x,y}", "map?1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"{+x,hello,y}", "1024,Hello%20World!,768"},
+		{"{+path,x}/here", "/foo/bar,1024/here"},
+		{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+		{"{#path,x}/here", "#/foo/bar,1024/here"},
+		{"X{.var}", "X.value"},
+		{"X{.x,y}", "X.1024.768"},
+		{"{/var}", "/value"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{;x,y}", ";x=1024;y=768"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{?x,y}", "?x=1024&y=768"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{&x,y,empty}", "&x=1024&y=768&empty="},
+		// level 4
+		{"{var:3}", "val"},
+		{"{var:30}", "value"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "comma,%2C,dot,.,semi,%3B"},
+		{"{keys*}", "comma=%2C,dot=.,semi=%3B"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{+list}", "red,green,blue"},
+		{"{+list*}", "red,green,blue"},
+		{"{+keys}", "comma,,,dot,.,semi,;"},
+		{"{+keys*}", "comma=,,dot=.,semi=;"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"{#list}", "#red,green,blue"},
+		{"{#list*}", "#red,green,blue"},
+		{"{#keys}", "#comma,,,dot,.,semi,;"},
+		{"{#keys*}", "#comma=,,dot=.,semi=;"},
+		{"X{.var:3}", "X.val"},
+		{"X{.list}", "X.red,green,blue"},
+		{"X{.list*}", "X.red.green.blue"},
+		{"X{.keys}", "X.comma,%2C,dot,.,semi,%3B"},
+		{"X{.keys*}", "X.comma=%2C.dot=..semi=%3B"},
+		{"X{.empty_keys}", "X"},
+		{"{/var:1,var}", "/v/value"},
+		{"{/list}", "/red,green,blue"},
+		{"{/list*}", "/red/green/blue"},
+		{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"{/keys}", "/comma,%2C,dot,.,semi,%3B"},
+		{"{/keys*}", "/comma=%2C/dot=./semi=%3B"},
+		{"{;hello:5}", ";hello=Hello"},
+		{"{;list}", ";list=red,green,blue"},
+		{"{;list*}", ";list=red;list=green;list=blue"},
+		{"{;keys}", ";keys=comma,%2C,dot,.,semi,%3B"},
+		{"{;keys*}", ";comma=%2C;dot=.;semi=%3B"},
+		{"{?var:3}", "?var=val"},
+		{"{?list}", "?list=red,green,blue"},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{?keys}", "?keys=comma,%2C,dot,.,semi,%3B"},
+		{"{?keys*}", "?comma=%2C&dot=.&semi=%3B"},
+		{"{&var:3}", "&var=val"},
+		{"{&list}", "&list=red,green,blue"},
+		{"{&list*}", "&list=red&list=green&list=blue"},
+		{"{&keys}", "&keys=comma,%2C,dot,.,semi,%3B"},
+		{"{&keys*}", "&comma=%2C&dot=.&semi=%3B"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.template, func(t *testing.T) {
+			actualOutput, err := urlutils.ExpandTemplate(testCase.template, rfc6570Variables)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOutput, actualOutput)
+		})
+	}
+}
+
+func TestTemplateExpandStruct(t *testing.T) {
+	type filter struct {
+		State string `uri:"state"`
+		Sort  string `uri:"sort,omitempty"`
+	}
+	type issues struct {
+		Owner  string   `uri:"owner"`
+		Repo   string   `uri:"repo"`
+		State  *string  `uri:"state"`
+		Labels []string `uri:"labels"`
+		Filter filter   `uri:"filter"`
+		Hidden string   `uri:"-"`
+	}
+
+	state := "open"
+	template := urlutils.MustParseTemplate("/repos/{owner}/{repo}/issues{?state,labels*}{&filter*}")
+
+	actualOutput, err := template.Expand(issues{
+		Owner:  "Goldziher",
+		Repo:   "go-utils",
+		State:  &state,
+		Labels: []string{"bug", "help wanted"},
+		Filter: filter{State: "closed", Sort: "asc"},
+	}, "uri")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"/repos/Goldziher/go-utils/issues?state=open&labels=bug&labels=help%20wanted&state=closed&sort=asc",
+		actualOutput,
+	)
+
+	actualOutput, err = template.Expand(&issues{Owner: "a", Repo: "b"}, "uri")
+	assert.NoError(t, err)
+	assert.Equal(t, "/repos/a/b/issues&state=", actualOutput)
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	for _, template := range []string{"{", "}", "{}", "{var", "{=var}", "{var:0}", "{var:10000}", "{va r}", "{.var}x{"} {
+		t.Run(template, func(t *testing.T) {
+			_, err := urlutils.ParseTemplate(template)
+			assert.Error(t, err)
+		})
+	}
+
+	assert.Panics(t, func() {
+		urlutils.MustParseTemplate("{")
+	})
+
+	_, err := urlutils.ExpandTemplate("{list:2}", rfc6570Variables)
+	assert.Error(t, err)
+}
+
+func TestTemplateVariables(t *testing.T) {
+	template := urlutils.MustParseTemplate("/repos/{owner}/{repo}{/path*}{?owner,q}")
+	assert.Equal(t, []string{"owner", "repo", "path", "q"}, template.Variables())
+	assert.Equal(t, "/repos/{owner}/{repo}{/path*}{?owner,q}", template.String())
+}
+
+func TestTemplateMatch(t *testing.T) {
+	testCases := []struct {
+		template       string
+		uri            string
+		expectedOutput map[string]any
+	}{
+		{
+			"/repos/{owner}/{repo}/issues{?state,labels*}",
+			"/repos/Goldziher/go-utils/issues?state=open&labels=bug&labels=help%20wanted",
+			map[string]any{"owner": "Goldziher", "repo": "go-utils", "state": "open", "labels": []string{"bug", "help wanted"}},
+		},
+		{
+			"/repos/{owner}/{repo}/issues{?state,labels*}",
+			"/repos/Goldziher/go-utils/issues",
+			map[string]any{"owner": "Goldziher", "repo": "go-utils"},
+		},
+		{
+			"{+base}/files{/path*}{.ext}",
+			"http://example.com/files/a/b/c.txt",
+			map[string]any{"base": "http://example.com", "path": []string{"a", "b", "c"}, "ext": "txt"},
+		},
+		{
+			"/search{?q,filter*}",
+			"/search?q=go&lang=en&sort=stars",
+			map[string]any{"q": "go", "filter": map[string]string{"lang": "en", "sort": "stars"}},
+		},
+		{
+			"map?{x,y}",
+			"map?1024,768",
+			map[string]any{"x": "1024", "y": "768"},
+		},
+		{
+			"{;x,y,empty}",
+			";x=1024;y=768;empty",
+			map[string]any{"x": "1024", "y": "768", "empty": ""},
+		},
+		{
+			"/items{/id}{#section}",
+			"/items/42#details",
+			map[string]any{"id": "42", "section": "details"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.uri, func(t *testing.T) {
+			actualOutput, ok := urlutils.MustParseTemplate(testCase.template).Match(testCase.uri)
+			assert.True(t, ok)
+			assert.Equal(t, testCase.expectedOutput, actualOutput)
+		})
+	}
+
+	t.Run("Test no match", func(t *testing.T) {
+		template := urlutils.MustParseTemplate("/repos/{owner}/{repo}")
+		_, ok := template.Match("/users/Goldziher")
+		assert.False(t, ok)
+		_, ok = template.Match("/repos/a/b/c")
+		assert.False(t, ok)
+
+		_, ok = urlutils.MustParseTemplate("/search{?q}").Match("/search?other=1")
+		assert.False(t, ok)
+	})
+
+	t.Run("Test round trip", func(t *testing.T) {
+		template := urlutils.MustParseTemplate("/repos/{owner}/{repo}/issues{?state,labels*}")
+		variables := map[string]any{"owner": "a b", "repo": "c", "state": "open", "labels": []string{"x", "y"}}
+
+		expanded, err := template.Expand(variables)
+		assert.NoError(t, err)
+
+		matched, ok := template.Match(expanded)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]any{"owner": "a b", "repo": "c", "state": "open", "labels": []string{"x", "y"}}, matched)
+	})
+}
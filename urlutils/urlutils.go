@@ -4,8 +4,16 @@
 package urlutils
 
 import (
+	"cmp"
+	"encoding"
+	"errors"
+	"fmt"
 	"net/url"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Goldziher/go-utils/stringutils"
 )
@@ -34,6 +42,7 @@ func QueryStringifyMap[K comparable, V any](values map[K]V) string {
 }
 
 // QueryStringifyStruct creates a query string from a given struct instance. Takes struct tag names as optional parameters.
+// Tag values may carry the omitempty and omitzero options, for example `qs:"name,omitempty"`.
 func QueryStringifyStruct[T any](values T, structTags ...string) string {
 	query := url.Values{}
 
@@ -42,23 +51,19 @@ func QueryStringifyStruct[T any](values T, structTags ...string) string {
 	fields := reflect.VisibleFields(typeOf)
 
 	for _, field := range fields {
-		key := field.Name
+		tag := parseFieldTag(field, structTags)
 
-		if field.Tag != "" {
-			for _, s := range structTags {
-				if tagValue, isPresent := field.Tag.Lookup(s); isPresent && tagValue != "" {
-					key = tagValue
-					break
-				}
-			}
-		}
-
-		if key == "-" {
+		if tag.name == "-" {
 			continue
 		}
 
+		key := tag.name
 		value := valueOf.FieldByName(field.Name)
 
+		if tag.omit(value) {
+			continue
+		}
+
 		if value.Kind() == reflect.Slice {
 			if value.IsNil() {
 				query.Add(key, "")
@@ -75,6 +80,923 @@ func QueryStringifyStruct[T any](values T, structTags ...string) string {
 	return query.Encode()
 }
 
+// DefaultValueTag is the struct tag used by ParseQueryStruct to read a field's default value.
+const DefaultValueTag = "default"
+
+// ParamError describes a single query parameter that could not be decoded.
+type ParamError struct {
+	Key   string
+	Value string
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("invalid value %q for query parameter %q: %v", e.Value, e.Key, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// QueryParseError is returned by ParseQueryStruct and lists every query parameter that failed to decode.
+type QueryParseError struct {
+	Params []*ParamError
+}
+
+func (e *QueryParseError) Error() string {
+	messages := make([]string, len(e.Params))
+	for i, param := range e.Params {
+		messages[i] = param.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the individual parameter errors, allowing errors.Is and errors.As to inspect them.
+func (e *QueryParseError) Unwrap() []error {
+	errs := make([]error, len(e.Params))
+	for i, param := range e.Params {
+		errs[i] = param
+	}
+	return errs
+}
+
+// NestingFormat selects how nested maps and structs are encoded into query keys.
+type NestingFormat int
+
+const (
+	// NestingNone stringifies nested values as a whole, which is the behavior of QueryStringifyStruct.
+	NestingNone NestingFormat = iota
+	// NestingBrackets uses Rails/PHP/qs style keys, for example filter[status]=open.
+	NestingBrackets
+	// NestingDots uses dot notation as understood by Spring, for example filter.status=open.
+	NestingDots
+)
+
+// ArrayFormat selects how slices and arrays are encoded.
+type ArrayFormat int
+
+const (
+	// ArrayRepeat repeats the key for every element: ids=1&ids=2.
+	ArrayRepeat ArrayFormat = iota
+	// ArrayBrackets appends empty brackets to the key: ids[]=1&ids[]=2.
+	ArrayBrackets
+	// ArrayIndices appends the element index to the key: ids[0]=1&ids[1]=2.
+	ArrayIndices
+	// ArrayComma joins the elements into a single value: ids=1,2.
+	ArrayComma
+)
+
+// DefaultTimeLayout is the layout used to encode time.Time values when QueryOptions.TimeLayout is not set.
+const DefaultTimeLayout = time.RFC3339Nano
+
+// QueryMarshaler is implemented by types that encode themselves as a single query value.
+// It takes precedence over encoding.TextMarshaler and fmt.Stringer.
+type QueryMarshaler interface {
+	MarshalQuery() (string, error)
+}
+
+// QueryUnmarshaler is implemented by types that decode themselves from a single query value.
+// It takes precedence over encoding.TextUnmarshaler.
+type QueryUnmarshaler interface {
+	UnmarshalQuery(value string) error
+}
+
+// QueryOptions - query encoding and decoding options.
+type QueryOptions struct {
+	StructTags       []string
+	Nesting          NestingFormat
+	Arrays           ArrayFormat
+	OmitEmpty        bool
+	OmitZero         bool
+	TimeLayout       string
+	StringifyOptions stringutils.Options
+}
+
+func parseQueryOptions(opts ...QueryOptions) QueryOptions {
+	options := QueryOptions{TimeLayout: DefaultTimeLayout}
+
+	for _, opt := range opts {
+		if len(opt.StructTags) > 0 {
+			options.StructTags = opt.StructTags
+		}
+		if opt.Nesting != NestingNone {
+			options.Nesting = opt.Nesting
+		}
+		if opt.Arrays != ArrayRepeat {
+			options.Arrays = opt.Arrays
+		}
+		if opt.OmitEmpty {
+			options.OmitEmpty = true
+		}
+		if opt.OmitZero {
+			options.OmitZero = true
+		}
+		if opt.TimeLayout != "" {
+			options.TimeLayout = opt.TimeLayout
+		}
+		if opt.StringifyOptions != (stringutils.Options{}) {
+			options.StringifyOptions = opt.StringifyOptions
+		}
+	}
+
+	return options
+}
+
+// QueryStringify creates a query string from a map or struct instance, encoding nested values as selected by the options.
+// The output is deterministic: keys are sorted and repeated values keep their order.
+// QueryStringify also accepts an options object with the following properties:
+//
+//	QueryOptions.StructTags: struct tag names used to resolve keys, as in QueryStringifyStruct.
+//	QueryOptions.Nesting: the encoding for nested maps and structs, defaults to NestingNone.
+//	QueryOptions.Arrays: the encoding for slices and arrays, defaults to ArrayRepeat.
+//	QueryOptions.OmitEmpty: omit false, 0, nil and empty values, as the omitempty tag option does for a single field.
+//	QueryOptions.OmitZero: omit zero values and values whose IsZero method returns true, as the omitzero tag option does.
+//	QueryOptions.TimeLayout: the layout used for time.Time values, defaults to RFC3339 with nanoseconds.
+//	QueryOptions.StringifyOptions: the stringutils.Options used for numbers and other plain values.
+//
+// Values implementing QueryMarshaler or encoding.TextMarshaler encode themselves, and an error returned by them is
+// returned to the caller.
+// Slices of maps or structs are always encoded with indices, since the other array formats cannot represent them.
+// Values that are neither maps nor structs (or pointers to them) produce an empty string.
+func QueryStringify(value any, opts ...QueryOptions) (string, error) {
+	query, err := QueryValues(value, opts...)
+	if err != nil {
+		return "", err
+	}
+	return query.Encode(), nil
+}
+
+// QueryValues is like QueryStringify but returns the url.Values instead of the encoded query string.
+func QueryValues(value any, opts ...QueryOptions) (url.Values, error) {
+	options := parseQueryOptions(opts...)
+	query := url.Values{}
+
+	valueOf := reflect.ValueOf(value)
+	for valueOf.Kind() == reflect.Ptr || valueOf.Kind() == reflect.Interface {
+		valueOf = valueOf.Elem()
+	}
+
+	if valueOf.Kind() != reflect.Struct && valueOf.Kind() != reflect.Map {
+		return query, nil
+	}
+
+	if err := encodeQueryChildren(query, "", valueOf, options); err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// encodeQueryChildren encodes the fields of a struct or the entries of a map under the given key prefix.
+func encodeQueryChildren(query url.Values, prefix string, value reflect.Value, options QueryOptions) error {
+	global := queryTag{omitEmpty: options.OmitEmpty, omitZero: options.OmitZero}
+
+	if value.Kind() == reflect.Map {
+		for _, mapKey := range value.MapKeys() {
+			entry := value.MapIndex(mapKey)
+			if global.omit(entry) {
+				continue
+			}
+
+			key := joinQueryKey(prefix, stringutils.Stringify(mapKey.Interface(), options.StringifyOptions), options.Nesting)
+			if err := encodeQueryValue(query, key, entry, options); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		if !field.IsExported() || (field.Anonymous && derefType(field.Type).Kind() == reflect.Struct) {
+			continue
+		}
+
+		tag := parseFieldTag(field, options.StructTags)
+		if tag.name == "-" {
+			continue
+		}
+
+		// fields promoted through a nil embedded struct pointer are left out.
+		fieldValue, err := value.FieldByIndexErr(field.Index)
+		if err != nil {
+			continue
+		}
+		if tag.omit(fieldValue) || global.omit(fieldValue) {
+			continue
+		}
+
+		if err := encodeQueryValue(query, joinQueryKey(prefix, tag.name, options.Nesting), fieldValue, options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeQueryValue adds a single value, recursing into nested values when a nesting format is selected.
+func encodeQueryValue(query url.Values, key string, value reflect.Value, options QueryOptions) error {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		query.Add(key, "")
+		return nil
+	}
+
+	if isQuerySequence(value) {
+		return encodeQuerySequence(query, key, value, options)
+	}
+
+	if options.Nesting == NestingNone || isQueryLeaf(value.Type()) {
+		encoded, err := encodeQueryLeaf(value, options)
+		if err != nil {
+			return fmt.Errorf("urlutils: encoding query parameter %q: %w", key, err)
+		}
+		query.Add(key, encoded)
+		return nil
+	}
+
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			query.Add(key, "")
+			return nil
+		}
+		return encodeQueryValue(query, key, value.Elem(), options)
+	}
+
+	return encodeQueryChildren(query, key, value, options)
+}
+
+// encodeQuerySequence adds the elements of a slice or array using the selected array format.
+func encodeQuerySequence(query url.Values, key string, value reflect.Value, options QueryOptions) error {
+	if value.Kind() == reflect.Slice && value.IsNil() {
+		query.Add(key, "")
+		return nil
+	}
+
+	nested := options.Nesting != NestingNone && !isQueryLeaf(derefType(value.Type().Elem()))
+
+	if options.Arrays == ArrayIndices || nested {
+		for i := 0; i < value.Len(); i++ {
+			if err := encodeQueryValue(query, key+"["+strconv.Itoa(i)+"]", value.Index(i), options); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	elements := make([]string, value.Len())
+	for i := range elements {
+		encoded, err := encodeQueryLeaf(value.Index(i), options)
+		if err != nil {
+			return fmt.Errorf("urlutils: encoding query parameter %q: %w", key, err)
+		}
+		elements[i] = encoded
+	}
+
+	switch options.Arrays {
+	case ArrayComma:
+		query.Add(key, strings.Join(elements, ","))
+	case ArrayBrackets:
+		query[key+"[]"] = append(query[key+"[]"], elements...)
+	default:
+		query[key] = append(query[key], elements...)
+	}
+
+	return nil
+}
+
+// encodeQueryLeaf converts a single value into its query representation.
+func encodeQueryLeaf(value reflect.Value, options QueryOptions) (string, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+
+	if marshaler, ok := queryInterface[QueryMarshaler](value); ok {
+		return marshaler.MarshalQuery()
+	}
+
+	if value.Type() == timeType {
+		return value.Interface().(time.Time).Format(options.TimeLayout), nil
+	}
+
+	if marshaler, ok := queryInterface[encoding.TextMarshaler](value); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	return stringutils.Stringify(value.Interface(), options.StringifyOptions), nil
+}
+
+// queryInterface returns the value as I, also considering methods declared on the pointer receiver.
+func queryInterface[I any](value reflect.Value) (I, bool) {
+	var zero I
+
+	if implemented, ok := value.Interface().(I); ok {
+		return implemented, true
+	}
+
+	if !reflect.PointerTo(value.Type()).Implements(reflect.TypeFor[I]()) {
+		return zero, false
+	}
+
+	if value.CanAddr() {
+		return value.Addr().Interface().(I), true
+	}
+
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	return pointer.Interface().(I), true
+}
+
+// joinQueryKey appends a nested key segment to a prefix.
+func joinQueryKey(prefix, key string, nesting NestingFormat) string {
+	switch {
+	case prefix == "":
+		return key
+	case nesting == NestingDots:
+		return prefix + "." + key
+	default:
+		return prefix + "[" + key + "]"
+	}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isQuerySequence reports whether a value is a slice or array that is encoded element by element.
+func isQuerySequence(value reflect.Value) bool {
+	kind := value.Kind()
+	return (kind == reflect.Slice || kind == reflect.Array) && value.Type().Elem().Kind() != reflect.Uint8
+}
+
+var (
+	stringerType         = reflect.TypeFor[fmt.Stringer]()
+	textMarshalerType    = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType  = reflect.TypeFor[encoding.TextUnmarshaler]()
+	queryMarshalerType   = reflect.TypeFor[QueryMarshaler]()
+	queryUnmarshalerType = reflect.TypeFor[QueryUnmarshaler]()
+	timeType             = reflect.TypeFor[time.Time]()
+	durationType         = reflect.TypeFor[time.Duration]()
+)
+
+// isQueryLeaf reports whether a type is encoded as a single value rather than recursed into.
+func isQueryLeaf(t reflect.Type) bool {
+	for _, leafType := range []reflect.Type{
+		stringerType, textMarshalerType, textUnmarshalerType, queryMarshalerType, queryUnmarshalerType,
+	} {
+		if t.Implements(leafType) || reflect.PointerTo(t).Implements(leafType) {
+			return true
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Interface:
+		return false
+	case reflect.Ptr:
+		return isQueryLeaf(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() == reflect.Uint8
+	default:
+		return true
+	}
+}
+
+// ParseQueryStruct decodes url.Values into a new instance of the struct T. It is the inverse of QueryStringifyStruct.
+// Takes struct tag names as optional parameters, resolved the same way as in QueryStringifyStruct.
+//
+// Repeated keys are collected into slice fields, while scalar fields use the first value.
+// Supported field types are strings, bools, ints, uints, floats, time.Time (RFC3339 or date only), time.Duration,
+// pointers to these, and any type implementing encoding.TextUnmarshaler.
+// Fields missing from the query are populated from their `default:"..."` tag when present.
+// Fields of embedded structs are decoded as fields of T, allocating nil embedded struct pointers when needed.
+// If any parameter fails to decode, a *QueryParseError listing each bad parameter is returned along with the
+// partially populated struct.
+func ParseQueryStruct[T any](values url.Values, structTags ...string) (T, error) {
+	var result T
+
+	if kind := reflect.TypeFor[T]().Kind(); kind != reflect.Struct {
+		return result, fmt.Errorf("urlutils: ParseQueryStruct expects a struct type, got %s", kind)
+	}
+
+	return ParseQuery[T](values, QueryOptions{StructTags: structTags})
+}
+
+// ParseQuery decodes url.Values into a new instance of T, the matching decoder of QueryStringify.
+// T can be a struct, a map with string keys, or any, in which case nested values are decoded into
+// map[string]any and []any with string leaves.
+// The options should match the ones used for encoding; with ArrayComma, values are split on commas into slices,
+// while "[]" and "[n]" array suffixes are always understood, so every ArrayFormat round trips.
+// Field decoding, default values and errors behave as in ParseQueryStruct.
+func ParseQuery[T any](values url.Values, opts ...QueryOptions) (T, error) {
+	var result T
+
+	options := parseQueryOptions(opts...)
+	root := buildQueryTree(values, options.Nesting)
+
+	var params []*ParamError
+	decodeQueryNode(reflect.ValueOf(&result).Elem(), root, "", options, &params)
+
+	if len(params) > 0 {
+		return result, &QueryParseError{Params: params}
+	}
+
+	return result, nil
+}
+
+// queryNode is a single key in the tree built from nested query keys.
+type queryNode struct {
+	values   []string
+	children map[string]*queryNode
+	order    []string
+	array    bool
+}
+
+func (n *queryNode) child(key string) *queryNode {
+	if n.children == nil {
+		n.children = map[string]*queryNode{}
+	}
+	if existing, ok := n.children[key]; ok {
+		return existing
+	}
+	created := &queryNode{}
+	n.children[key] = created
+	n.order = append(n.order, key)
+	return created
+}
+
+// indexedChildren returns the children with integer keys ordered by index, and whether all children are indexed.
+// Keys with the same index, such as "0" and "00", are all kept, ordered by key.
+func (n *queryNode) indexedChildren() ([]*queryNode, bool) {
+	type indexedKey struct {
+		key   string
+		index int
+	}
+
+	keys := make([]indexedKey, 0, len(n.order))
+	for _, key := range n.order {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 {
+			return nil, false
+		}
+		keys = append(keys, indexedKey{key: key, index: index})
+	}
+	slices.SortFunc(keys, func(a, b indexedKey) int {
+		return cmp.Or(cmp.Compare(a.index, b.index), strings.Compare(a.key, b.key))
+	})
+
+	result := make([]*queryNode, len(keys))
+	for i, key := range keys {
+		result[i] = n.children[key.key]
+	}
+	return result, true
+}
+
+func buildQueryTree(values url.Values, nesting NestingFormat) *queryNode {
+	root := &queryNode{}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		path, isArray := splitQueryKey(key, nesting)
+
+		node := root
+		for _, segment := range path {
+			node = node.child(segment)
+		}
+		node.values = append(node.values, values[key]...)
+		node.array = node.array || isArray
+	}
+
+	return root
+}
+
+// splitQueryKey splits a nested key into its path segments and reports whether it ends with empty brackets.
+// Keys that are not well-formed are treated as a single literal segment. Without nesting, only a trailing "[]" or
+// "[n]" array suffix is split off.
+func splitQueryKey(key string, nesting NestingFormat) ([]string, bool) {
+	if nesting == NestingNone {
+		return splitArraySuffix(key)
+	}
+
+	var path []string
+	isArray := false
+	current := strings.Builder{}
+
+	for i := 0; i < len(key); i++ {
+		switch {
+		case key[i] == '[' && i > 0:
+			end := strings.IndexByte(key[i:], ']')
+			if end < 0 {
+				return []string{key}, false
+			}
+			if current.Len() > 0 || len(path) == 0 {
+				path = append(path, current.String())
+				current.Reset()
+			}
+			segment := key[i+1 : i+end]
+			i += end
+			if segment == "" && i == len(key)-1 {
+				isArray = true
+				continue
+			}
+			path = append(path, segment)
+		case key[i] == '.' && nesting == NestingDots && i > 0 && i < len(key)-1:
+			if current.Len() > 0 {
+				path = append(path, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(key[i])
+		}
+	}
+
+	if current.Len() > 0 {
+		path = append(path, current.String())
+	}
+
+	return path, isArray
+}
+
+// splitArraySuffix splits a trailing "[]" or "[n]" off a key, as written by ArrayBrackets and ArrayIndices.
+func splitArraySuffix(key string) ([]string, bool) {
+	open := strings.LastIndexByte(key, '[')
+	if open <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}, false
+	}
+
+	index := key[open+1 : len(key)-1]
+	if index == "" {
+		return []string{key[:open]}, true
+	}
+	if strings.Trim(index, "0123456789") != "" {
+		return []string{key}, false
+	}
+	return []string{key[:open], index}, false
+}
+
+// decodeQueryNode decodes a query tree node into target, collecting errors into params.
+func decodeQueryNode(target reflect.Value, node *queryNode, key string, options QueryOptions, params *[]*ParamError) {
+	addError := func(value string, err error) {
+		*params = append(*params, &ParamError{Key: key, Value: value, Err: err})
+	}
+
+	if target.Kind() == reflect.Ptr && !isQueryLeaf(target.Type()) {
+		element := reflect.New(target.Type().Elem())
+		decodeQueryNode(element.Elem(), node, key, options, params)
+		target.Set(element)
+		return
+	}
+
+	switch {
+	case isQueryLeaf(target.Type()):
+		if len(node.values) == 0 {
+			addError("", errors.New("expected a value but got nested keys"))
+			return
+		}
+		if err := decodeQueryValue(target, node.values[0], options); err != nil {
+			addError(node.values[0], err)
+		}
+	case target.Kind() == reflect.Slice:
+		decodeQuerySlice(target, node, key, options, params)
+	case target.Kind() == reflect.Struct:
+		decodeQueryStruct(target, node, key, options, params)
+	case target.Kind() == reflect.Map:
+		decodeQueryMap(target, node, key, options, params)
+	case target.Kind() == reflect.Interface && target.NumMethod() == 0:
+		target.Set(reflect.ValueOf(node.generic(options)))
+	default:
+		addError(strings.Join(node.values, ","), fmt.Errorf("unsupported type %s", target.Type()))
+	}
+}
+
+func decodeQueryStruct(target reflect.Value, node *queryNode, key string, options QueryOptions, params *[]*ParamError) {
+	for _, field := range reflect.VisibleFields(target.Type()) {
+		if !field.IsExported() || (field.Anonymous && derefType(field.Type).Kind() == reflect.Struct) {
+			continue
+		}
+
+		name := parseFieldTag(field, options.StructTags).name
+		if name == "-" {
+			continue
+		}
+
+		child := node.children[name]
+		if child == nil || (len(child.values) == 0 && len(child.children) == 0) {
+			defaultValue, hasDefault := field.Tag.Lookup(DefaultValueTag)
+			if !hasDefault {
+				continue
+			}
+			child = &queryNode{values: []string{defaultValue}}
+		}
+
+		fieldValue, ok := allocateFieldByIndex(target, field.Index)
+		if !ok {
+			continue
+		}
+		decodeQueryNode(fieldValue, child, joinQueryKey(key, name, options.Nesting), options, params)
+	}
+}
+
+// allocateFieldByIndex returns the nested field of target like reflect.Value.FieldByIndex, allocating nil embedded
+// struct pointers on the way. It returns false if a nil embedded pointer cannot be set because it is unexported.
+func allocateFieldByIndex(target reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && target.Kind() == reflect.Ptr {
+			if target.IsNil() {
+				if !target.CanSet() {
+					return reflect.Value{}, false
+				}
+				target.Set(reflect.New(target.Type().Elem()))
+			}
+			target = target.Elem()
+		}
+		target = target.Field(fieldIndex)
+	}
+	return target, true
+}
+
+func decodeQueryMap(target reflect.Value, node *queryNode, key string, options QueryOptions, params *[]*ParamError) {
+	mapType := target.Type()
+	if target.IsNil() {
+		target.Set(reflect.MakeMapWithSize(mapType, len(node.order)))
+	}
+
+	for _, name := range node.order {
+		childKey := joinQueryKey(key, name, options.Nesting)
+
+		mapKey := reflect.New(mapType.Key()).Elem()
+		if err := decodeQueryValue(mapKey, name, options); err != nil {
+			*params = append(*params, &ParamError{Key: childKey, Value: name, Err: err})
+			continue
+		}
+
+		element := reflect.New(mapType.Elem()).Elem()
+		decodeQueryNode(element, node.children[name], childKey, options, params)
+		target.SetMapIndex(mapKey, element)
+	}
+}
+
+func decodeQuerySlice(target reflect.Value, node *queryNode, key string, options QueryOptions, params *[]*ParamError) {
+	indexed, _ := node.indexedChildren()
+	elementType := target.Type().Elem()
+
+	if !isQueryLeaf(elementType) {
+		slice := reflect.MakeSlice(target.Type(), len(indexed), len(indexed))
+		for i, child := range indexed {
+			decodeQueryNode(slice.Index(i), child, key+"["+strconv.Itoa(i)+"]", options, params)
+		}
+		target.Set(slice)
+		return
+	}
+
+	raw := node.sequenceValues(options)
+	for _, child := range indexed {
+		raw = append(raw, child.values...)
+	}
+
+	slice := reflect.MakeSlice(target.Type(), 0, len(raw))
+	for _, value := range raw {
+		element := reflect.New(elementType).Elem()
+		if err := decodeQueryValue(element, value, options); err != nil {
+			*params = append(*params, &ParamError{Key: key, Value: value, Err: err})
+			continue
+		}
+		slice = reflect.Append(slice, element)
+	}
+	target.Set(slice)
+}
+
+// sequenceValues returns the node's values, split on commas when the comma array format is selected.
+func (n *queryNode) sequenceValues(options QueryOptions) []string {
+	if options.Arrays != ArrayComma {
+		return n.values
+	}
+
+	var result []string
+	for _, value := range n.values {
+		result = append(result, strings.Split(value, ",")...)
+	}
+	return result
+}
+
+// generic converts the node into map[string]any, []any or string values.
+func (n *queryNode) generic(options QueryOptions) any {
+	if len(n.children) > 0 {
+		if indexed, ok := n.indexedChildren(); ok {
+			result := make([]any, len(indexed))
+			for i, child := range indexed {
+				result[i] = child.generic(options)
+			}
+			return result
+		}
+
+		result := make(map[string]any, len(n.children))
+		for key, child := range n.children {
+			result[key] = child.generic(options)
+		}
+		return result
+	}
+
+	values := n.sequenceValues(options)
+	if len(values) == 1 && !n.array {
+		return values[0]
+	}
+
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// decodeQueryValue converts a single raw query value into target.
+func decodeQueryValue(target reflect.Value, raw string, options QueryOptions) error {
+	if target.Kind() == reflect.Ptr {
+		element := reflect.New(target.Type().Elem())
+		if err := decodeQueryValue(element.Elem(), raw, options); err != nil {
+			return err
+		}
+		target.Set(element)
+		return nil
+	}
+
+	if target.CanAddr() && target.Addr().Type().Implements(queryUnmarshalerType) {
+		return target.Addr().Interface().(QueryUnmarshaler).UnmarshalQuery(raw)
+	}
+
+	if target.CanAddr() && target.Addr().Type().Implements(textUnmarshalerType) && target.Type() != timeType {
+		return target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch target.Type() {
+	case timeType:
+		parsed, err := parseQueryTime(raw, options.TimeLayout)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(parsed))
+		return nil
+	case durationType:
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		target.SetInt(int64(parsed))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		target.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetFloat(parsed)
+	case reflect.Slice:
+		if target.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", target.Type())
+		}
+		// byte slices are decoded as raw strings, matching how Stringify encodes them
+		target.SetBytes([]byte(raw))
+	default:
+		return fmt.Errorf("unsupported type %s", target.Type())
+	}
+
+	return nil
+}
+
+// queryTimeLayouts are tried in order after the configured layout when decoding time.Time values.
+// The last layout matches time.Time.String, which is what QueryStringifyStruct produces for time values.
+var queryTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.DateTime,
+	time.DateOnly,
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+func parseQueryTime(raw string, layout string) (time.Time, error) {
+	if layout != "" {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			return parsed, nil
+		}
+	}
+
+	for _, layout := range queryTimeLayouts {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, errors.New("unrecognized time format")
+}
+
+// queryTag holds the key and options resolved from a struct field's tags.
+type queryTag struct {
+	name      string
+	omitEmpty bool
+	omitZero  bool
+}
+
+// parseFieldTag resolves the query key and options for a struct field using the first matching struct tag,
+// falling back to the field name. Tag values have the form "name,omitempty,omitzero", where the name may be empty.
+func parseFieldTag(field reflect.StructField, structTags []string) queryTag {
+	tag := queryTag{name: field.Name}
+
+	if field.Tag == "" {
+		return tag
+	}
+
+	for _, s := range structTags {
+		tagValue, isPresent := field.Tag.Lookup(s)
+		if !isPresent || tagValue == "" {
+			continue
+		}
+
+		name, tagOptions, _ := strings.Cut(tagValue, ",")
+		if name != "" {
+			tag.name = name
+		}
+		for _, option := range strings.Split(tagOptions, ",") {
+			switch option {
+			case "omitempty":
+				tag.omitEmpty = true
+			case "omitzero":
+				tag.omitZero = true
+			}
+		}
+		break
+	}
+
+	return tag
+}
+
+// omit reports whether the value should be left out of the query according to the tag options.
+func (t queryTag) omit(value reflect.Value) bool {
+	if !value.IsValid() {
+		return t.omitEmpty || t.omitZero
+	}
+	return (t.omitEmpty && isEmptyQueryValue(value)) || (t.omitZero && isZeroQueryValue(value))
+}
+
+// isEmptyQueryValue follows the omitempty semantics of encoding/json.
+func isEmptyQueryValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return value.IsZero()
+	default:
+		return false
+	}
+}
+
+// isZeroQueryValue reports whether a value is zero, preferring an IsZero method when the type declares one.
+func isZeroQueryValue(value reflect.Value) bool {
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return true
+	}
+	if zeroer, ok := queryInterface[interface{ IsZero() bool }](value); ok {
+		return zeroer.IsZero()
+	}
+	return value.IsZero()
+}
+
 // Parse parses a raw URL and returns a URL struct or an error.
 // This is a convenience wrapper around url.Parse.
 func Parse(rawURL string) (*url.URL, error) {
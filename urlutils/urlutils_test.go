@@ -1,9 +1,14 @@
 package urlutils_test
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/Goldziher/go-utils/stringutils"
 	"github.com/Goldziher/go-utils/urlutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -182,3 +187,434 @@ func TestGetPath(t *testing.T) {
 	assert.Equal(t, "", urlutils.GetPath("https://example.com"))
 	assert.Equal(t, "", urlutils.GetPath("://invalid"))
 }
+
+type queryLevel int
+
+func (l *queryLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+func TestParseQueryStruct(t *testing.T) {
+	type searchQuery struct {
+		Query    string        `qs:"q"`
+		Page     int           `qs:"page" default:"1"`
+		PageSize uint8         `qs:"page_size" default:"20"`
+		Ratio    float64       `qs:"ratio"`
+		Active   bool          `qs:"active"`
+		Tags     []string      `qs:"tag"`
+		IDs      []int         `qs:"id"`
+		Since    time.Time     `qs:"since"`
+		Timeout  time.Duration `qs:"timeout"`
+		Limit    *int          `qs:"limit"`
+		Level    queryLevel    `qs:"level"`
+		Ignored  string        `qs:"-"`
+		internal string
+	}
+
+	t.Run("Test decodes all supported types", func(t *testing.T) {
+		values, _ := url.ParseQuery(
+			"q=golang&page=3&ratio=0.5&active=true&tag=a&tag=b&id=1&id=2&since=2024-01-02T03:04:05Z" +
+				"&timeout=1m30s&limit=10&level=high&Ignored=x",
+		)
+
+		result, err := urlutils.ParseQueryStruct[searchQuery](values, "qs")
+		assert.NoError(t, err)
+		assert.Equal(t, "golang", result.Query)
+		assert.Equal(t, 3, result.Page)
+		assert.Equal(t, uint8(20), result.PageSize)
+		assert.Equal(t, 0.5, result.Ratio)
+		assert.True(t, result.Active)
+		assert.Equal(t, []string{"a", "b"}, result.Tags)
+		assert.Equal(t, []int{1, 2}, result.IDs)
+		assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), result.Since)
+		assert.Equal(t, 90*time.Second, result.Timeout)
+		assert.Equal(t, 10, *result.Limit)
+		assert.Equal(t, queryLevel(2), result.Level)
+		assert.Empty(t, result.Ignored)
+		assert.Empty(t, result.internal)
+	})
+
+	t.Run("Test applies defaults for missing keys", func(t *testing.T) {
+		result, err := urlutils.ParseQueryStruct[searchQuery](url.Values{}, "qs")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Page)
+		assert.Equal(t, uint8(20), result.PageSize)
+		assert.Nil(t, result.Limit)
+		assert.Nil(t, result.Tags)
+	})
+
+	t.Run("Test lists each bad parameter", func(t *testing.T) {
+		values, _ := url.ParseQuery("page=abc&page_size=300&active=maybe&id=1&id=x&level=medium")
+
+		result, err := urlutils.ParseQueryStruct[searchQuery](values, "qs")
+		assert.Error(t, err)
+
+		var parseErr *urlutils.QueryParseError
+		assert.True(t, errors.As(err, &parseErr))
+
+		keys := make([]string, len(parseErr.Params))
+		for i, param := range parseErr.Params {
+			keys[i] = param.Key
+		}
+		assert.Equal(t, []string{"page", "page_size", "active", "id", "level"}, keys)
+		assert.Equal(t, "x", parseErr.Params[3].Value)
+		assert.Equal(t, []int{1}, result.IDs)
+
+		var numErr *strconv.NumError
+		assert.True(t, errors.As(err, &numErr))
+	})
+
+	t.Run("Test round trips QueryStringifyStruct", func(t *testing.T) {
+		type roundTrip struct {
+			User    string
+			Active  bool
+			Age     int `qs:"age"`
+			Friends []int
+		}
+		input := roundTrip{User: "moishe", Active: true, Age: 100, Friends: []int{1, 2, 3}}
+
+		values, _ := url.ParseQuery(urlutils.QueryStringifyStruct(input, "qs"))
+		result, err := urlutils.ParseQueryStruct[roundTrip](values, "qs")
+		assert.NoError(t, err)
+		assert.Equal(t, input, result)
+	})
+
+	t.Run("Test decodes fields of embedded struct pointers", func(t *testing.T) {
+		type Paging struct {
+			A int `qs:"a"`
+			B int `qs:"b"`
+		}
+		type outer struct {
+			*Paging
+			Query string `qs:"q"`
+		}
+
+		result, err := urlutils.ParseQueryStruct[outer](url.Values{"a": {"1"}, "b": {"2"}}, "qs")
+		assert.NoError(t, err)
+		assert.Equal(t, &Paging{A: 1, B: 2}, result.Paging)
+
+		result, err = urlutils.ParseQueryStruct[outer](url.Values{"q": {"x"}}, "qs")
+		assert.NoError(t, err)
+		assert.Equal(t, outer{Query: "x"}, result)
+
+		type unexported struct {
+			*nestedFilter
+		}
+		assert.NotPanics(t, func() {
+			_, err := urlutils.ParseQueryStruct[unexported](url.Values{"status": {"open"}}, "qs")
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("Test non struct type", func(t *testing.T) {
+		_, err := urlutils.ParseQueryStruct[map[string]string](url.Values{})
+		assert.Error(t, err)
+	})
+}
+
+type nestedFilter struct {
+	Status string   `qs:"status"`
+	Tags   []string `qs:"tags"`
+}
+
+type nestedQuery struct {
+	Filter nestedFilter   `qs:"filter"`
+	IDs    []int          `qs:"ids"`
+	Sort   map[string]int `qs:"sort"`
+	Items  []nestedFilter `qs:"items"`
+}
+
+var nestedInput = nestedQuery{
+	Filter: nestedFilter{Status: "open", Tags: []string{"a", "b"}},
+	IDs:    []int{1, 2},
+	Sort:   map[string]int{"created": -1},
+	Items:  []nestedFilter{{Status: "x", Tags: []string{"y"}}},
+}
+
+func TestQueryStringify(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        urlutils.QueryOptions
+		expectedOutput string
+	}{
+		{
+			"brackets with bracket arrays",
+			urlutils.QueryOptions{Nesting: urlutils.NestingBrackets, Arrays: urlutils.ArrayBrackets},
+			"filter[status]=open&filter[tags][]=a&filter[tags][]=b&ids[]=1&ids[]=2" +
+				"&items[0][status]=x&items[0][tags][]=y&sort[created]=-1",
+		},
+		{
+			"brackets with indexed arrays",
+			urlutils.QueryOptions{Nesting: urlutils.NestingBrackets, Arrays: urlutils.ArrayIndices},
+			"filter[status]=open&filter[tags][0]=a&filter[tags][1]=b&ids[0]=1&ids[1]=2" +
+				"&items[0][status]=x&items[0][tags][0]=y&sort[created]=-1",
+		},
+		{
+			"dots with repeated arrays",
+			urlutils.QueryOptions{Nesting: urlutils.NestingDots},
+			"filter.status=open&filter.tags=a&filter.tags=b&ids=1&ids=2" +
+				"&items[0].status=x&items[0].tags=y&sort.created=-1",
+		},
+		{
+			"dots with comma arrays",
+			urlutils.QueryOptions{Nesting: urlutils.NestingDots, Arrays: urlutils.ArrayComma},
+			"filter.status=open&filter.tags=a,b&ids=1,2&items[0].status=x&items[0].tags=y&sort.created=-1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.options.StructTags = []string{"qs"}
+			encoded, err := urlutils.QueryStringify(&nestedInput, testCase.options)
+			assert.NoError(t, err)
+			actualOutput, _ := url.QueryUnescape(encoded)
+			assert.Equal(t, testCase.expectedOutput, actualOutput)
+
+			values, _ := url.ParseQuery(encoded)
+			decoded, err := urlutils.ParseQuery[nestedQuery](values, testCase.options)
+			assert.NoError(t, err)
+			assert.Equal(t, nestedInput, decoded)
+		})
+	}
+
+	t.Run("Test no nesting matches QueryStringifyStruct", func(t *testing.T) {
+		input := struct {
+			User    string
+			Friends []int
+			Missing []int
+		}{User: "moishe", Friends: []int{1, 2}}
+		actualOutput, err := urlutils.QueryStringify(input)
+		assert.NoError(t, err)
+		assert.Equal(t, urlutils.QueryStringifyStruct(input), actualOutput)
+	})
+
+	t.Run("Test nil embedded struct pointer", func(t *testing.T) {
+		type Paging struct {
+			Page int `qs:"page"`
+		}
+		type search struct {
+			*Paging
+			Query string `qs:"q"`
+		}
+
+		actualOutput, err := urlutils.QueryStringify(search{Query: "x"}, urlutils.QueryOptions{StructTags: []string{"qs"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "q=x", actualOutput)
+
+		actualOutput, err = urlutils.QueryStringify(search{Paging: &Paging{Page: 2}, Query: "x"}, urlutils.QueryOptions{StructTags: []string{"qs"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "page=2&q=x", actualOutput)
+	})
+
+	t.Run("Test non map or struct", func(t *testing.T) {
+		actualOutput, err := urlutils.QueryStringify(42)
+		assert.NoError(t, err)
+		assert.Equal(t, "", actualOutput)
+	})
+}
+
+func TestParseQuery(t *testing.T) {
+	t.Run("Test generic brackets", func(t *testing.T) {
+		values, _ := url.ParseQuery("filter[status]=open&filter[range][min]=1&ids[]=1&ids[]=2&items[1]=b&items[0]=a&q=x")
+		result, err := urlutils.ParseQuery[map[string]any](values, urlutils.QueryOptions{Nesting: urlutils.NestingBrackets})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"filter": map[string]any{"status": "open", "range": map[string]any{"min": "1"}},
+			"ids":    []any{"1", "2"},
+			"items":  []any{"a", "b"},
+			"q":      "x",
+		}, result)
+	})
+
+	t.Run("Test generic dots with comma arrays", func(t *testing.T) {
+		values, _ := url.ParseQuery("filter.status=open&ids=1,2")
+		result, err := urlutils.ParseQuery[any](values, urlutils.QueryOptions{
+			Nesting: urlutils.NestingDots,
+			Arrays:  urlutils.ArrayComma,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"filter": map[string]any{"status": "open"},
+			"ids":    []any{"1", "2"},
+		}, result)
+	})
+
+	t.Run("Test nested errors report full keys", func(t *testing.T) {
+		values, _ := url.ParseQuery("ids[]=1&ids[]=x&sort[created]=up")
+		_, err := urlutils.ParseQuery[nestedQuery](values, urlutils.QueryOptions{
+			StructTags: []string{"qs"},
+			Nesting:    urlutils.NestingBrackets,
+		})
+
+		var parseErr *urlutils.QueryParseError
+		assert.True(t, errors.As(err, &parseErr))
+		assert.Len(t, parseErr.Params, 2)
+		assert.Equal(t, "ids", parseErr.Params[0].Key)
+		assert.Equal(t, "sort[created]", parseErr.Params[1].Key)
+	})
+
+	t.Run("Test non-canonical indices", func(t *testing.T) {
+		type idList struct {
+			IDs []int `qs:"ids"`
+		}
+		options := urlutils.QueryOptions{StructTags: []string{"qs"}, Nesting: urlutils.NestingBrackets}
+
+		result, err := urlutils.ParseQuery[idList](url.Values{"ids[01]": {"1"}}, options)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, result.IDs)
+
+		values, _ := url.ParseQuery("ids[1]=3&ids[0]=1&ids[00]=2")
+		result, err = urlutils.ParseQuery[idList](values, options)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, result.IDs)
+	})
+
+	t.Run("Test array formats round trip", func(t *testing.T) {
+		type idList struct {
+			IDs []int `qs:"ids"`
+		}
+		input := map[string]any{"ids": []int{1, 2}}
+
+		for _, nesting := range []urlutils.NestingFormat{urlutils.NestingNone, urlutils.NestingBrackets, urlutils.NestingDots} {
+			for _, arrays := range []urlutils.ArrayFormat{
+				urlutils.ArrayRepeat, urlutils.ArrayBrackets, urlutils.ArrayIndices, urlutils.ArrayComma,
+			} {
+				options := urlutils.QueryOptions{StructTags: []string{"qs"}, Nesting: nesting, Arrays: arrays}
+
+				encoded, err := urlutils.QueryStringify(input, options)
+				assert.NoError(t, err)
+				values, _ := url.ParseQuery(encoded)
+				result, err := urlutils.ParseQuery[idList](values, options)
+				assert.NoError(t, err, encoded)
+				assert.Equal(t, []int{1, 2}, result.IDs, encoded)
+			}
+		}
+
+		values, _ := url.ParseQuery("filter[status]=open&ids[x]=1")
+		result, err := urlutils.ParseQuery[map[string]string](values)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"filter[status]": "open", "ids[x]": "1"}, result)
+	})
+
+	t.Run("Test malformed keys are literal", func(t *testing.T) {
+		values, _ := url.ParseQuery("a[b=1&[c]=2")
+		result, err := urlutils.ParseQuery[map[string]string](values, urlutils.QueryOptions{Nesting: urlutils.NestingBrackets})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a[b": "1", "[c]": "2"}, result)
+	})
+}
+
+type queryMoney struct {
+	Cents int
+}
+
+func (m queryMoney) MarshalQuery() (string, error) {
+	if m.Cents < 0 {
+		return "", errors.New("negative amount")
+	}
+	return fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100), nil
+}
+
+func (m *queryMoney) UnmarshalQuery(value string) error {
+	var units, cents int
+	if _, err := fmt.Sscanf(value, "%d.%d", &units, &cents); err != nil {
+		return err
+	}
+	m.Cents = units*100 + cents
+	return nil
+}
+
+type queryColor struct {
+	Name string
+}
+
+func (c *queryColor) MarshalText() ([]byte, error) {
+	return []byte("color:" + c.Name), nil
+}
+
+func TestQueryStringifyOptions(t *testing.T) {
+	type options struct {
+		Name     string            `url:"name,omitempty"`
+		Tags     []string          `url:"tags,omitempty"`
+		Count    int               `url:",omitempty"`
+		Since    time.Time         `url:"since,omitzero"`
+		Price    queryMoney        `url:"price"`
+		Color    queryColor        `url:"color"`
+		Ratio    float64           `url:"ratio"`
+		Extra    map[string]string `url:"extra"`
+		Optional *int              `url:"optional"`
+	}
+
+	t.Run("Test tag options omit empty and zero values", func(t *testing.T) {
+		actualOutput, err := urlutils.QueryStringify(options{}, urlutils.QueryOptions{StructTags: []string{"url"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "color=color%3A&extra=%7B%7D&optional=&price=0.00&ratio=0.00", actualOutput)
+	})
+
+	t.Run("Test global omit options", func(t *testing.T) {
+		actualOutput, err := urlutils.QueryStringify(options{Price: queryMoney{Cents: 150}}, urlutils.QueryOptions{
+			StructTags: []string{"url"},
+			OmitEmpty:  true,
+			OmitZero:   true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "price=1.50", actualOutput)
+	})
+
+	t.Run("Test time layout and stringify options", func(t *testing.T) {
+		actualOutput, err := urlutils.QueryStringify(options{
+			Name:  "x",
+			Since: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Ratio: 0.125,
+			Color: queryColor{Name: "red"},
+		}, urlutils.QueryOptions{
+			StructTags:       []string{"url"},
+			OmitEmpty:        true,
+			TimeLayout:       time.DateOnly,
+			StringifyOptions: stringutils.Options{Precision: 3},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "color=color%3Ared&name=x&price=0.00&ratio=0.125&since=2024-01-02", actualOutput)
+	})
+
+	t.Run("Test marshaler errors are returned", func(t *testing.T) {
+		_, err := urlutils.QueryStringify(options{Price: queryMoney{Cents: -1}}, urlutils.QueryOptions{
+			StructTags: []string{"url"},
+		})
+		assert.ErrorContains(t, err, "price")
+	})
+
+	t.Run("Test round trip with unmarshalers and time layout", func(t *testing.T) {
+		type roundTrip struct {
+			Price queryMoney `url:"price"`
+			Since time.Time  `url:"since"`
+		}
+		input := roundTrip{Price: queryMoney{Cents: 1234}, Since: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+		opts := urlutils.QueryOptions{StructTags: []string{"url"}, TimeLayout: "02/01/2006"}
+
+		query, err := urlutils.QueryValues(input, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "02/01/2024", query.Get("since"))
+
+		decoded, err := urlutils.ParseQuery[roundTrip](query, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, input, decoded)
+	})
+
+	t.Run("Test QueryStringifyStruct honors tag options", func(t *testing.T) {
+		input := struct {
+			User    string `qs:"user,omitempty"`
+			Friends []int  `qs:"friends,omitempty"`
+			Age     int    `qs:"age,omitzero"`
+		}{User: "moishe"}
+		assert.Equal(t, "user=moishe", urlutils.QueryStringifyStruct(input, "qs"))
+	})
+}